@@ -0,0 +1,138 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SizeBudget limits the total size of the assets matching a pattern.
+type SizeBudget struct {
+	// Pattern is matched against the asset names, after they were
+	// transformed, e.g. "videos/**" or "**/*.png". See TransformConfig
+	// for the syntax.
+	Pattern string
+
+	// MaxSize is the maximum total size of the matching assets in bytes.
+	MaxSize int64
+}
+
+// BudgetViolation describes a single size limit which was exceeded.
+type BudgetViolation struct {
+	Limit   string   // "asset", "total" or the pattern of a SizeBudget.
+	MaxSize int64    // Size limit in bytes.
+	Size    int64    // Actual size in bytes.
+	Paths   []string // Full paths of the offending files, largest first.
+}
+
+// BudgetError is returned by Translate when the embedded assets exceed
+// any of the configured size limits.
+type BudgetError []BudgetViolation
+
+// maxBudgetPaths is the number of files listed per violated limit
+// in the error message.
+const maxBudgetPaths = 10
+
+// String describes the violation in a single line.
+func (v BudgetViolation) String() string {
+	var limit string
+	switch v.Limit {
+	case "asset":
+		limit = "asset size"
+	case "total":
+		limit = "total size"
+	default:
+		limit = fmt.Sprintf("size of %q", v.Limit)
+	}
+	paths := v.Paths
+	if len(paths) > maxBudgetPaths {
+		paths = append(paths[:maxBudgetPaths:maxBudgetPaths], fmt.Sprintf("and %d more", len(v.Paths)-maxBudgetPaths))
+	}
+	return fmt.Sprintf("%s %d bytes exceeds %d bytes: %s", limit, v.Size, v.MaxSize, strings.Join(paths, ", "))
+}
+
+func (e BudgetError) Error() string {
+	s := make([]string, 0, len(e)+1)
+	s = append(s, fmt.Sprintf("Exceeded %d size limit(s):", len(e)))
+	for _, v := range e {
+		s = append(s, "\t"+v.String())
+	}
+	return strings.Join(s, "\n")
+}
+
+// checkBudgets checks the assets recorded in the report against the size
+// limits of the given configuration. Limits apply to the embedded size of
+// an asset; debug builds embed nothing, so the source size is used there.
+//
+// If BudgetWarnOnly is set, the violations are added to the report
+// warnings instead of being returned as a BudgetError.
+func checkBudgets(c *Config, r *Report) error {
+	if c.MaxAssetSize == 0 && c.MaxTotalSize == 0 && len(c.SizeBudgets) == 0 {
+		return nil
+	}
+
+	assets := make([]AssetReport, len(r.Assets))
+	copy(assets, r.Assets)
+	size := func(a *AssetReport) int64 {
+		if c.Debug {
+			return a.Size
+		}
+		return a.Stored
+	}
+	sort.SliceStable(assets, func(i, j int) bool {
+		return size(&assets[i]) > size(&assets[j])
+	})
+
+	var e BudgetError
+	var total int64
+	for i := range assets {
+		n := size(&assets[i])
+		total += n
+		if c.MaxAssetSize > 0 && n > c.MaxAssetSize {
+			e = append(e, BudgetViolation{
+				Limit:   "asset",
+				MaxSize: c.MaxAssetSize,
+				Size:    n,
+				Paths:   []string{assets[i].Path},
+			})
+		}
+	}
+
+	if c.MaxTotalSize > 0 && total > c.MaxTotalSize {
+		v := BudgetViolation{Limit: "total", MaxSize: c.MaxTotalSize, Size: total}
+		for i := range assets {
+			v.Paths = append(v.Paths, assets[i].Path)
+		}
+		e = append(e, v)
+	}
+
+	for _, b := range c.SizeBudgets {
+		v := BudgetViolation{Limit: b.Pattern, MaxSize: b.MaxSize}
+		for i := range assets {
+			if matchPattern(b.Pattern, assets[i].Name) {
+				v.Size += size(&assets[i])
+				v.Paths = append(v.Paths, assets[i].Path)
+			}
+		}
+		if v.Size > b.MaxSize {
+			e = append(e, v)
+		}
+	}
+
+	if len(e) == 0 {
+		return nil
+	}
+
+	if c.BudgetWarnOnly {
+		for _, v := range e {
+			r.Warnings = append(r.Warnings, v.String())
+		}
+		return nil
+	}
+
+	return e
+}
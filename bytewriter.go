@@ -5,40 +5,59 @@
 package bindata
 
 import (
-	"fmt"
 	"io"
 )
 
 var (
 	newline    = []byte{'\n'}
-	dataindent = []byte{'\t', '\t'}
+	dataindent = []byte{'\t'}
 	space      = []byte{' '}
 )
 
+// chunkSize is the number of input bytes encoded before the output
+// is handed to the underlying writer at once.
+const chunkSize = 32 << 10
+
+// byteLit holds the "0xNN," literal of each byte value.
+var byteLit = func() (t [256][5]byte) {
+	for i := range t {
+		t[i] = [5]byte{'0', 'x', lowerHex[i>>4], lowerHex[i&0xf], ','}
+	}
+	return t
+}()
+
+// ByteWriter writes the bytes as the elements of a []byte literal,
+// twelve per line.
 type ByteWriter struct {
 	io.Writer
-	c int
+	c   int
+	buf []byte
 }
 
+// Write encodes p in chunks and writes them to the underlying writer.
+// It returns the number of bytes of p, whose encoding was written,
+// and the first error of the underlying writer.
 func (w *ByteWriter) Write(p []byte) (n int, err error) {
-	if len(p) == 0 {
-		return
-	}
-
-	for n = range p {
-		if w.c%12 == 0 {
-			w.Writer.Write(newline)
-			w.Writer.Write(dataindent)
-			w.c = 0
-		} else {
-			w.Writer.Write(space)
+	for len(p) != 0 {
+		chunk := p[:min(len(p), chunkSize)]
+		buf := w.buf[:0]
+		for _, b := range chunk {
+			if w.c%12 == 0 {
+				buf = append(buf, newline[0], dataindent[0])
+				w.c = 0
+			} else {
+				buf = append(buf, space[0])
+			}
+			buf = append(buf, byteLit[b][:]...)
+			w.c++
 		}
+		w.buf = buf
 
-		fmt.Fprintf(w.Writer, "0x%02x,", p[n])
-		w.c++
+		if _, err = w.Writer.Write(buf); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
 	}
-
-	n++
-
-	return
+	return n, nil
 }
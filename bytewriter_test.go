@@ -0,0 +1,245 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"errors"
+	"go/parser"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// allBytes holds every byte value, followed by text, which is kept
+// as it is by the compact encoding.
+var allBytes = func() []byte {
+	p := make([]byte, 0, 512)
+	for i := 0; i < 256; i++ {
+		p = append(p, byte(i))
+	}
+	return append(p, "<p class=\"x\">`zażółć`\r\n\t\\x41\xef\xbb\xbf</p>\n"...)
+}()
+
+// inputs are the contents encoded by the tests, including ones spanning
+// several chunks.
+var inputs = [][]byte{
+	nil,
+	[]byte("a"),
+	[]byte("plain text\n"),
+	allBytes,
+	bytes.Repeat(allBytes, 2*chunkSize/len(allBytes)+1),
+	bytes.Repeat([]byte("text without escapes "), chunkSize/10),
+}
+
+// encoders wrap a data literal around the encoded input, as the release
+// code does.
+var encoders = []struct {
+	name   string
+	encode func(w io.Writer, p []byte) error
+}{
+	{"ByteWriter", func(w io.Writer, p []byte) error {
+		return writeLit(w, "[]byte{", &ByteWriter{Writer: w}, p, "\n}")
+	}},
+	{"StringWriter", func(w io.Writer, p []byte) error {
+		return writeLit(w, `"`, &StringWriter{Writer: w}, p, `"`)
+	}},
+	{"CompactStringWriter", func(w io.Writer, p []byte) error {
+		return writeLit(w, `[]byte("`, &CompactStringWriter{Writer: w}, p, `")`)
+	}},
+}
+
+func writeLit(w io.Writer, open string, enc io.Writer, p []byte, close string) error {
+	if _, err := io.WriteString(w, open); err != nil {
+		return err
+	}
+	if _, err := enc.Write(p); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, close)
+	return err
+}
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, enc := range encoders {
+		for i, p := range inputs {
+			var buf bytes.Buffer
+			if err := enc.encode(&buf, p); err != nil {
+				t.Errorf("%s: want err=nil; got %v (i=%d)", enc.name, err, i)
+				continue
+			}
+			expr, err := parser.ParseExpr(buf.String())
+			if err != nil {
+				t.Errorf("%s: want err=nil; got %v (i=%d)", enc.name, err, i)
+				continue
+			}
+			q, err := dataLit(expr)
+			if err != nil {
+				t.Errorf("%s: want err=nil; got %v (i=%d)", enc.name, err, i)
+				continue
+			}
+			if !bytes.Equal(p, q) {
+				t.Errorf("%s: decoded data differs from the input (i=%d)", enc.name, i)
+			}
+		}
+	}
+}
+
+func TestParseFileRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	in := filepath.Join(dir, "in")
+	if err := os.Mkdir(in, 0755); err != nil {
+		t.Fatal(err)
+	}
+	names := []string{"empty", "a", "text", "bytes", "chunks", "raw"}
+	for i, name := range names {
+		if err := ioutil.WriteFile(filepath.Join(in, name), inputs[i], 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	modes := []struct {
+		name string
+		set  func(c *Config)
+	}{
+		{"memcopy", func(c *Config) {}},
+		{"nomemcopy", func(c *Config) { c.NoMemCopy = true }},
+		{"compact", func(c *Config) { c.Compact = true }},
+		{"compact-nomemcopy", func(c *Config) { c.Compact, c.NoMemCopy = true, true }},
+		{"nocompress", func(c *Config) { c.NoCompress = true }},
+		{"nocompress-nomemcopy", func(c *Config) { c.NoCompress, c.NoMemCopy = true, true }},
+		{"nocompress-compact", func(c *Config) { c.NoCompress, c.Compact = true, true }},
+		{"nocompress-compact-nomemcopy", func(c *Config) { c.NoCompress, c.Compact, c.NoMemCopy = true, true, true }},
+	}
+	for _, mode := range modes {
+		c := NewConfig()
+		c.Package = "main"
+		c.Prefix = in
+		c.Output = filepath.Join(dir, mode.name+".go")
+		c.Input = []InputConfig{{Path: in}}
+		mode.set(c)
+		if err := Translate(c); err != nil {
+			t.Errorf("%s: want err=nil; got %v", mode.name, err)
+			continue
+		}
+		assets, err := ParseFile(c.Output)
+		if err != nil {
+			t.Errorf("%s: want err=nil; got %v", mode.name, err)
+			continue
+		}
+		if len(assets) != len(names) {
+			t.Errorf("%s: want len(assets)=%d; got %d", mode.name, len(names), len(assets))
+			continue
+		}
+		for _, asset := range assets {
+			p, err := asset.Content()
+			if err != nil {
+				t.Errorf("%s: %s: want err=nil; got %v", mode.name, asset.Name, err)
+				continue
+			}
+			want, err := ioutil.ReadFile(filepath.Join(in, asset.Name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(p, want) {
+				t.Errorf("%s: %s: decoded content differs from the file", mode.name, asset.Name)
+			}
+		}
+	}
+}
+
+var errWrite = errors.New("write failed")
+
+// failWriter fails once more than n bytes were written to it.
+type failWriter struct {
+	n int
+}
+
+func (w *failWriter) Write(p []byte) (int, error) {
+	if len(p) > w.n {
+		return 0, errWrite
+	}
+	w.n -= len(p)
+	return len(p), nil
+}
+
+func TestWriterError(t *testing.T) {
+	p := inputs[4]
+	writers := []struct {
+		name string
+		new  func(w io.Writer) io.Writer
+	}{
+		{"ByteWriter", func(w io.Writer) io.Writer { return &ByteWriter{Writer: w} }},
+		{"StringWriter", func(w io.Writer) io.Writer { return &StringWriter{Writer: w} }},
+		{"CompactStringWriter", func(w io.Writer) io.Writer { return &CompactStringWriter{Writer: w} }},
+	}
+	for _, wr := range writers {
+		for _, limit := range []int{0, 2 * chunkSize} {
+			n, err := wr.new(&failWriter{n: limit}).Write(p)
+			if err != errWrite {
+				t.Errorf("%s: want err=%v; got %v (limit=%d)", wr.name, errWrite, err, limit)
+			}
+			if n >= len(p) || n%chunkSize != 0 {
+				t.Errorf("%s: want n to be a multiple of %d below %d; got %d (limit=%d)",
+					wr.name, chunkSize, len(p), n, limit)
+			}
+		}
+	}
+}
+
+// benchSize is the size of the benchmark input.
+const benchSize = 100 << 20
+
+var (
+	benchOnce  sync.Once
+	benchInput []byte
+)
+
+// benchData returns 100MB of input: random bytes interleaved with text,
+// like a mix of compressed and uncompressed assets.
+func benchData() []byte {
+	benchOnce.Do(func() {
+		benchInput = make([]byte, benchSize)
+		r := rand.New(rand.NewSource(1))
+		r.Read(benchInput)
+		text := []byte("<div class=\"content\">Lorem ipsum dolor sit amet.</div>\n")
+		for i := 0; i+len(text) <= len(benchInput); i += 2 * len(text) {
+			copy(benchInput[i:], text)
+		}
+	})
+	return benchInput
+}
+
+func benchmarkWriter(b *testing.B, w io.Writer) {
+	p := benchData()
+	b.SetBytes(int64(len(p)))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := w.Write(p); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkByteWriter(b *testing.B) {
+	benchmarkWriter(b, &ByteWriter{Writer: ioutil.Discard})
+}
+
+func BenchmarkStringWriter(b *testing.B) {
+	benchmarkWriter(b, &StringWriter{Writer: ioutil.Discard})
+}
+
+func BenchmarkCompactStringWriter(b *testing.B) {
+	benchmarkWriter(b, &CompactStringWriter{Writer: ioutil.Discard})
+}
@@ -0,0 +1,132 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeCache writes the in-process cache of decompressed assets.
+// This targets release builds.
+func writeCache(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// AssetCacheInfo describes the state of the asset cache.
+type AssetCacheInfo struct {
+	Hits    uint64 // Number of Asset calls served from the cache.
+	Misses  uint64 // Number of Asset calls which loaded the asset.
+	Size    int64  // Total size of the cached assets in bytes.
+	Entries int    // Number of cached assets.
+	Limit   int64  // Cache size limit in bytes; zero or less if unbounded.
+}
+
+type bindata_cache_entry struct {
+	name string
+	data []byte
+}
+
+var _bindata_cache = struct {
+	sync.Mutex
+	info    AssetCacheInfo
+	lru     *list.List
+	entries map[string]*list.Element
+}{
+	lru:     list.New(),
+	entries: make(map[string]*list.Element),
+}
+
+// SetAssetCacheLimit bounds the total size in bytes of the decompressed
+// assets kept in the cache, evicting the least recently used ones first.
+// A limit of zero or less, which is the default, keeps every asset
+// in the cache once it was loaded.
+func SetAssetCacheLimit(limit int64) {
+	_bindata_cache.Lock()
+	_bindata_cache.info.Limit = limit
+	bindata_cache_evict()
+	_bindata_cache.Unlock()
+}
+
+// AssetCache returns the current state of the asset cache.
+func AssetCache() AssetCacheInfo {
+	_bindata_cache.Lock()
+	defer _bindata_cache.Unlock()
+	return _bindata_cache.info
+}
+
+// bindata_cached returns the asset data from the cache, loading it
+// with the given function on a miss. The returned slice is shared
+// between all callers and must not be modified.
+func bindata_cached(name string, read func() ([]byte, error)) ([]byte, error) {
+	c := &_bindata_cache
+
+	c.Lock()
+	if e, ok := c.entries[name]; ok {
+		c.info.Hits++
+		c.lru.MoveToFront(e)
+		c.Unlock()
+		return e.Value.(*bindata_cache_entry).data, nil
+	}
+	c.info.Misses++
+	c.Unlock()
+
+	data, err := read()
+	if err != nil {
+		return nil, err
+	}
+
+	c.Lock()
+	if _, ok := c.entries[name]; !ok {
+		c.entries[name] = c.lru.PushFront(&bindata_cache_entry{name, data})
+		c.info.Size += int64(len(data))
+		c.info.Entries++
+		bindata_cache_evict()
+	}
+	c.Unlock()
+
+	return data, nil
+}
+
+// bindata_cache_evict drops the least recently used assets until the cache
+// fits within its limit. It must be called with the cache locked.
+func bindata_cache_evict() {
+	c := &_bindata_cache
+	for c.info.Limit > 0 && c.info.Size > c.info.Limit {
+		e := c.lru.Back()
+		entry := e.Value.(*bindata_cache_entry)
+		c.lru.Remove(e)
+		delete(c.entries, entry.name)
+		c.info.Size -= int64(len(entry.data))
+		c.info.Entries--
+	}
+}
+
+`)
+	return err
+}
+
+// writeDebugCache writes the cache API for debug builds, which read the
+// assets from disk each time and cache nothing.
+func writeDebugCache(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// AssetCacheInfo describes the state of the asset cache.
+type AssetCacheInfo struct {
+	Hits    uint64 // Number of Asset calls served from the cache.
+	Misses  uint64 // Number of Asset calls which loaded the asset.
+	Size    int64  // Total size of the cached assets in bytes.
+	Entries int    // Number of cached assets.
+	Limit   int64  // Cache size limit in bytes; zero or less if unbounded.
+}
+
+// SetAssetCacheLimit does nothing in debug builds, which do not cache
+// the assets.
+func SetAssetCacheLimit(limit int64) {}
+
+// AssetCache returns an empty cache state in debug builds, which do not
+// cache the assets.
+func AssetCache() AssetCacheInfo {
+	return AssetCacheInfo{}
+}
+
+`)
+	return err
+}
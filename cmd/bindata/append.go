@@ -0,0 +1,39 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/rjeczalik/bindata"
+)
+
+func init() {
+	commands["append"] = appendPack
+}
+
+// appendPack appends a pack of the assets in the given input directories
+// to an executable, replacing the one appended before. It accepts the
+// options of the generator; the ones affecting the asset data must match
+// the options the loader of the executable was generated with.
+func appendPack(args []string) error {
+	os.Args = append(os.Args[:1], args...)
+	c, _, report := parseArgs()
+	if flag.NArg() < 2 {
+		return errors.New("usage: bindata append [options] <executable> <input directories>")
+	}
+	c.Input = c.Input[1:]
+	r, err := bindata.AppendPack(c, flag.Arg(0))
+	if err != nil {
+		return err
+	}
+	warn(r)
+	if report != "" {
+		printReport(r)
+	}
+	return nil
+}
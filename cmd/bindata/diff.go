@@ -0,0 +1,271 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rjeczalik/bindata"
+)
+
+func init() {
+	commands["diff"] = diff
+}
+
+// errDiffer is returned by diff when the compared asset sets differ.
+// It makes the program exit with status 1 without further output.
+var errDiffer = errors.New("assets differ")
+
+// maxEdits bounds the number of line edits computed for a text diff;
+// larger changes are reported like binary ones.
+const maxEdits = 4096
+
+// diff compares the assets of two generated files, or of a generated file
+// and an input directory, and reports the added, removed and modified ones.
+func diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	prefix := fs.String("prefix", "", "Optional path prefix to strip off asset names of an input directory.")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: bindata diff [-prefix path] <old> <new>\n\n"+
+			"Each of <old> and <new> is either a generated file or an input directory.\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("diff requires two arguments")
+	}
+	old, err := loadAssets(fs.Arg(0), *prefix)
+	if err != nil {
+		return err
+	}
+	new, err := loadAssets(fs.Arg(1), *prefix)
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(old)+len(new))
+	for name := range old {
+		names = append(names, name)
+	}
+	for name := range new {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	differ := false
+	for _, name := range names {
+		a, inOld := old[name]
+		b, inNew := new[name]
+		switch {
+		case !inOld:
+			fmt.Printf("A %s (%d bytes)\n", name, len(b))
+		case !inNew:
+			fmt.Printf("D %s (%d bytes)\n", name, len(a))
+		case bytes.Equal(a, b):
+			continue
+		default:
+			fmt.Printf("M %s\n", name)
+			if isText(a) && isText(b) && unified(os.Stdout, name, a, b) {
+				break
+			}
+			fmt.Printf("  size %d -> %d bytes, sha256 %x -> %x\n",
+				len(a), len(b), sha256.Sum256(a), sha256.Sum256(b))
+		}
+		differ = true
+	}
+	if differ {
+		return errDiffer
+	}
+	return nil
+}
+
+// loadAssets returns the content of the assets of a generated file, or of
+// an input directory, mapped to their names.
+func loadAssets(path string, prefix string) (map[string][]byte, error) {
+	assets := make(map[string][]byte)
+	if fi, err := os.Stat(path); err == nil && !fi.IsDir() {
+		embedded, err := bindata.ParseFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, asset := range embedded {
+			if assets[asset.Name], err = asset.Content(); err != nil {
+				return nil, fmt.Errorf("%s: %s: %v", path, asset.Name, err)
+			}
+		}
+		return assets, nil
+	}
+	c := bindata.NewConfig()
+	c.Prefix = prefix
+	c.Input = []bindata.InputConfig{parseInput(path)}
+	toc, err := bindata.Assets(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, asset := range toc {
+		if assets[asset.Name], err = ioutil.ReadFile(asset.Path); err != nil {
+			return nil, err
+		}
+	}
+	return assets, nil
+}
+
+// isText reports whether p looks like text rather than binary data.
+func isText(p []byte) bool {
+	return utf8.Valid(p) && bytes.IndexByte(p, 0) == -1
+}
+
+// edit is a single line of an edit script.
+type edit struct {
+	op   byte // ' ' for unchanged, '-' for removed or '+' for added line
+	line string
+}
+
+// unified writes the unified diff of a and b with three lines of context.
+// It returns false without writing anything if the texts differ too much.
+func unified(w io.Writer, name string, a, b []byte) bool {
+	edits := lineDiff(splitLines(a), splitLines(b))
+	if edits == nil {
+		return false
+	}
+	fmt.Fprintf(w, "--- a/%s\n+++ b/%s\n", name, name)
+	const context = 3
+	ai, bi := 0, 0 // line numbers of edits[i] in a and b
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i, ai, bi = i+1, ai+1, bi+1
+			continue
+		}
+		// Extend the hunk until context*2 unchanged lines separate
+		// it from the next change.
+		start := i - context
+		if start < 0 {
+			start = 0
+		}
+		end, same := i, 0
+		for ; end < len(edits) && same <= 2*context; end++ {
+			if edits[end].op == ' ' {
+				same++
+			} else {
+				same = 0
+			}
+		}
+		if same > context {
+			end -= same - context
+		}
+		as, bs := ai-(i-start), bi-(i-start)
+		var an, bn int
+		for _, e := range edits[start:end] {
+			if e.op != '+' {
+				an++
+			}
+			if e.op != '-' {
+				bn++
+			}
+		}
+		fmt.Fprintf(w, "@@ -%d,%d +%d,%d @@\n", as+1, an, bs+1, bn)
+		for _, e := range edits[start:end] {
+			fmt.Fprintf(w, "%c%s\n", e.op, e.line)
+		}
+		for _, e := range edits[i:end] {
+			if e.op != '+' {
+				ai++
+			}
+			if e.op != '-' {
+				bi++
+			}
+		}
+		i = end
+	}
+	return true
+}
+
+func splitLines(p []byte) []string {
+	s := strings.TrimSuffix(string(p), "\n")
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, "\n")
+}
+
+// lineDiff computes the shortest edit script turning a into b with
+// the Myers' algorithm. It returns nil if more than maxEdits edits
+// are needed.
+func lineDiff(a, b []string) []edit {
+	n, m := len(a), len(b)
+	max := n + m
+	off := max + 1
+	v := make([]int, 2*max+3)
+	var trace [][]int
+	for d := 0; d <= max && d <= maxEdits; d++ {
+		// Round d reads v[k-1] and v[k+1] for -d <= k <= d only.
+		trace = append(trace, append([]int(nil), v[off-d-1:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the trace of lineDiff back and returns the edit script.
+// The trace of round d holds the values of v for -d-1 <= k <= d+1.
+func backtrack(a, b []string, trace [][]int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for d := len(trace) - 1; d >= 0; d-- {
+		v, off := trace[d], d+1
+		k := x - y
+		var pk int
+		if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := v[off+pk]
+		py := px - pk
+		for x > px && y > py {
+			edits = append(edits, edit{' ', a[x-1]})
+			x, y = x-1, y-1
+		}
+		if d > 0 {
+			if x == px {
+				edits = append(edits, edit{'+', b[y-1]})
+			} else {
+				edits = append(edits, edit{'-', a[x-1]})
+			}
+		}
+		x, y = px, py
+	}
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
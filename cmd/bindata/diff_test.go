@@ -0,0 +1,57 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rjeczalik/bindata"
+)
+
+const fixture = "../../testdata/out/compress-memcopy.go"
+
+func TestDiffLegacy(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := bindata.NewConfig()
+	c.Package = "main"
+	c.Prefix = "../../testdata"
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Input = []bindata.InputConfig{{Path: "../../testdata/in", Recursive: true}}
+	if err := bindata.Translate(c); err != nil {
+		t.Fatal(err)
+	}
+
+	in := filepath.Join(dir, "in")
+	if err := os.MkdirAll(filepath.Join(in, "a"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(in, "a", "test.asset"), []byte("// changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		args []string
+		err  error
+	}{
+		{[]string{fixture, c.Output}, nil},
+		{[]string{c.Output, fixture}, nil},
+		{[]string{"-prefix", "../../testdata", fixture, "../../testdata/in/..."}, nil},
+		{[]string{"-prefix", dir, fixture, in + "/..."}, errDiffer},
+		{[]string{fixture, "../../testdata/out/nocompress-nomemcopy.go"}, nil},
+	}
+	for i, cas := range cases {
+		if err := diff(cas.args); err != cas.err {
+			t.Errorf("want err=%v; got %v (i=%d)", cas.err, err, i)
+		}
+	}
+}
@@ -34,6 +34,14 @@ To ignore files, pass in regexes using -ignore, for example:
 
     ~ $ bindata -ignore=\\.gitignore data/...
 
+To make sure no credentials end up in the binary, pass the -scan flag. The
+assets are then checked for private keys, AWS keys, high-entropy tokens and
+.env files before embedding, and the generation fails listing the offending
+files and lines. Known-safe files are excluded with -allow regexes, single
+lines with a "bindata:allow" comment:
+
+    ~ $ bindata -scan -allow=testdata/keys/ data/...
+
 Accessing an asset
 
 To access asset data, we use the `Asset(string) []byte` function which
@@ -46,6 +54,95 @@ is included in the generated output.
 
 	// use asset data
 
+Large assets can be streamed with the `AssetReader(string) (io.ReadCloser, error)`
+function instead. It decompresses the data lazily as it is read, or in debug
+builds reads it straight from the file on disk, so the whole asset never has
+to be held in memory at once.
+
+	r, err := AssetReader("pub/video/intro.webm")
+	if err != nil {
+		// Asset was not found.
+	}
+	defer r.Close()
+
+	// stream asset data
+
+Both functions return an `*AssetError` on failure, which records the name of
+the asset and wraps the underlying error. A missing asset wraps the
+`ErrAssetNotFound` sentinel, so it can be told apart from a corrupt one with
+`errors.Is`:
+
+	data, err := Asset("pub/style/foo.css")
+	if errors.Is(err, ErrAssetNotFound) {
+		// Asset was not found.
+	}
+
+For package-level initialization the `MustAsset(string) []byte` function
+panics with a descriptive message instead of returning an error.
+
+	var style = MustAsset("pub/style/foo.css")
+
+
+Regenerating files
+
+Each generated file records the command line which created it in its header,
+below the standard "Code generated by bindata; DO NOT EDIT." comment. The
+-regen flag runs that command again, in the same working directory, so a
+file can be refreshed without remembering its options:
+
+	~ $ bindata -regen web/bindata.go
+
+It fits go:generate workflows as well:
+
+	//go:generate bindata -regen bindata.go
+
+
+Generation reports
+
+The -report flag prints a report of the generated file to the standard
+output. The only supported format is json; it lists the source path, name,
+function, source and embedded size, compression ratio, SHA-256 digest and
+processing time of each asset, and the totals. In the automatic mode the
+reports of all the generated files are printed as a single JSON array.
+
+	~ $ bindata -report json -o bindata.go data/...
+
+
+Size budgets
+
+The -maxasset and -maxtotal flags limit the embedded size of a single asset
+and of all the assets together; the -budget flag, which can be repeated,
+limits the total size of the assets matching a pattern. Sizes are given in
+bytes or with a K, M or G suffix. When a limit is exceeded, generation fails
+listing the offending files, or only prints a warning with -budgetwarn.
+
+	~ $ bindata -maxasset 4M -maxtotal 64M -budget 'images/*=16M' data/...
+
+
+Inspecting generated files
+
+The `ls`, `cat` and `extract` subcommands read the assets back from an
+existing, release build of a generated file. They help to find out which
+version of an asset got baked into a release.
+
+	~ $ bindata ls bindata.go
+	  SIZE  STORED  RATIO NAME
+	 10342    2714  26.2% pub/style/foo.css
+	~ $ bindata cat bindata.go pub/style/foo.css
+	~ $ bindata extract bindata.go /tmp/assets
+
+Encrypted assets are listed, but can not be decoded.
+
+The `diff` subcommand compares two generated files, or a generated file with
+a directory of assets, whose names are stripped of the -prefix path as
+the generator does. It lists added, deleted and modified assets,
+shows a unified diff for text assets and sizes and checksums for binary
+ones. It exits with status 1 when the assets differ, which makes it handy in
+CI to check that a committed file is up to date:
+
+	~ $ bindata diff old/bindata.go bindata.go
+	~ $ bindata diff -prefix data/ bindata.go data/...
+
 
 Debug vs Release builds
 
@@ -68,6 +165,99 @@ refresh in the browser to see those changes. Embedding the assets with the
 ready for deployment, just re-invoke `bindata` without the `-debug` flag.
 It will now embed the latest version of the assets.
 
+By default a debug build refers to the assets by their absolute paths, so it
+only works on the machine it was generated on. The `-root` flag makes it
+relocatable: asset paths are stored relative to the given directory and
+resolved at runtime against the generated `AssetRoot` variable, which
+defaults to the `-root` value, or against the `BINDATA_ROOT` environment
+variable, when it is set.
+
+	~ $ bindata -debug -root . data/...
+	~ $ BINDATA_ROOT=/src/project ./server
+
+Hybrid builds
+
+The `-override` flag generates a release build, which still embeds all the
+assets, but looks them up first in an override directory on disk. The
+directory is set at runtime with the generated `SetOverrideDir(string)`
+function or the `BINDATA_OVERRIDE` environment variable. When a file exists
+there under the asset's name, the disk copy wins over the embedded one.
+
+	~ $ bindata -override data/...
+	~ $ BINDATA_OVERRIDE=/etc/server/assets ./server
+
+This allows patching a template of a deployed program without rebuilding it,
+and iterating on assets against a release binary.
+
+Encrypted assets
+
+The `-encrypt` flag takes a file holding a hex-encoded AES key of 16, 24 or
+32 bytes. Each asset is then sealed with AES-GCM after compression, so its
+content can not be read from the binary, e.g. with `strings`. The key is not
+embedded; the program registers a function providing it at runtime:
+
+	SetAssetKeyProvider(func() ([]byte, error) {
+		return hex.DecodeString(os.Getenv("ASSET_KEY"))
+	})
+
+When no key is available, or an asset fails authentication with the given
+key, `Asset` returns an `*AssetError` wrapping `ErrAssetKeyMissing` or
+`ErrAssetDecrypt` respectively.
+
+Signed assets
+
+The `-sign` flag takes a file holding a hex-encoded ed25519 private key or
+its 32 byte seed. The SHA-256 digests of all embedded assets are signed with
+it at generation time, and the generated `VerifyAssets(ed25519.PublicKey)`
+function recomputes them at runtime and checks the signature, returning
+`ErrAssetSignature` if any asset was patched, added or removed:
+
+	if err := VerifyAssets(pub); err != nil {
+		log.Fatal(err)
+	}
+
+In debug builds `VerifyAssets` always succeeds.
+
+
+Compact literals
+
+The -compact flag writes the embedded data as shorter string literals,
+escaping only non-printable bytes, and uncompressed text assets as raw
+string literals. Combined with -nocompress, the generated source of text
+assets is about the size of the assets themselves.
+
+	~ $ bindata -compact -nocompress data/...
+
+
+Splitting large outputs
+
+The -maxfile flag limits the size of the asset data in a single generated
+file. The assets beyond it are written to shards next to the output file,
+bindata_001.go, bindata_002.go and so on, while the table of contents stays
+in bindata.go. Stale shards of previous runs are removed.
+
+	~ $ bindata -maxfile 16M -o bindata.go data/...
+
+
+Pack files
+
+The -pack flag stores the asset data in a separate pack file instead of the
+generated code, which then only holds a loader mapping the pack into memory
+at runtime. The loader opens the pack at the -packpath path, defaulting to
+the -pack one, unless the AssetPack variable or $BINDATA_PACK say otherwise.
+
+	~ $ bindata -pack assets.pack -o bindata.go data/...
+
+With -packexe the loader reads the pack appended to the executable, which
+the `append` subcommand writes to an already built program. Running it
+again replaces the appended pack, so release engineering can swap assets
+without recompiling. The options affecting the asset data, e.g. -prefix or
+-nocompress, must match the ones the loader was generated with.
+
+	~ $ bindata -packexe -o bindata.go data/...
+	~ $ go build -o app
+	~ $ bindata append ./app data/...
+
 
 Lower memory footprint
 
@@ -127,6 +317,44 @@ even increase the size of the data.
 
 The default behaviour of the program is to use compression.
 
+Minification
+
+The `-minify` flag takes a comma-separated list of asset kinds, which are
+minified before compression: `css`, `html`, `js`, `json` and `svg`. Assets
+are matched by their file extension and the size of each one before and
+after minification is printed. Debug builds are never minified.
+
+	~ $ bindata -minify css,js,json data/...
+	minify	style/foo.css	10342 -> 7211 bytes
+
+Caching assets
+
+Every call to `Asset` decompresses the asset anew. When the same assets are
+requested often, e.g. templates rendered on every request, the `-cache` flag
+makes the generated code keep the decompressed data in memory after it was
+first loaded. The cache is unbounded by default; the generated
+`SetAssetCacheLimit(int64)` function limits its total size in bytes, evicting
+the least recently used assets first, and `AssetCache()` reports its hits,
+misses and size.
+
+Slices returned from a cached `Asset` call are shared, so they must not be
+modified. Debug builds always read the assets from disk and ignore the flag.
+
+Templates
+
+The `-templates` flag takes a glob pattern of template assets, where a `**`
+path element matches any number of directories. The generated code then gets
+a `Templates() (*template.Template, error)` function, which parses all the
+matching assets into a single html/template set, each template named by its
+asset name. The `-texttemplates` flag switches it to text/template. Custom
+functions are registered with `SetTemplateFuncs` before parsing:
+
+	SetTemplateFuncs(template.FuncMap{"upper": strings.ToUpper})
+	tmpl := template.Must(Templates())
+
+The templates are parsed by `bindata` as well, so syntax errors fail the
+generation instead of the program at startup.
+
 Path prefix stripping
 
 The keys used in the `_bindata` map, are the same as the input file name
@@ -0,0 +1,118 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rjeczalik/bindata"
+)
+
+// commands maps subcommand names to their implementations.
+var commands = map[string]func(args []string) error{
+	"ls":      ls,
+	"cat":     cat,
+	"extract": extract,
+}
+
+// ls lists the assets embedded in a generated file with their sizes
+// and compression ratios.
+func ls(args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: bindata ls <file.go>")
+	}
+	assets, err := bindata.ParseFile(args[0])
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "SIZE\tSTORED\tRATIO\t NAME")
+	for _, asset := range assets {
+		p, err := asset.Content()
+		switch {
+		case err == bindata.ErrEncrypted:
+			fmt.Fprintf(w, "-\t%d\t-\t %s\n", len(asset.Data), asset.Name)
+		case err != nil:
+			return err
+		default:
+			fmt.Fprintf(w, "%d\t%d\t%s\t %s\n", len(p), len(asset.Data), ratio(len(asset.Data), len(p)), asset.Name)
+		}
+	}
+	return w.Flush()
+}
+
+// cat prints the content of a single asset embedded in a generated file.
+func cat(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: bindata cat <file.go> <name>")
+	}
+	asset, err := find(args[0], args[1])
+	if err != nil {
+		return err
+	}
+	p, err := asset.Content()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(p)
+	return err
+}
+
+// extract writes all the assets embedded in a generated file to the given
+// directory, each one under its name.
+func extract(args []string) error {
+	if len(args) != 2 {
+		return errors.New("usage: bindata extract <file.go> <dir>")
+	}
+	assets, err := bindata.ParseFile(args[0])
+	if err != nil {
+		return err
+	}
+	for _, asset := range assets {
+		path := filepath.Join(args[1], filepath.FromSlash(asset.Name))
+		if rel, err := filepath.Rel(args[1], path); err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("asset %q points outside of %s", asset.Name, args[1])
+		}
+		p, err := asset.Content()
+		if err != nil {
+			return fmt.Errorf("%s: %v", asset.Name, err)
+		}
+		if err = os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err = ioutil.WriteFile(path, p, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find looks up the asset of the given name in a generated file.
+func find(file, name string) (*bindata.EmbeddedAsset, error) {
+	assets, err := bindata.ParseFile(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, asset := range assets {
+		if asset.Name == name {
+			return asset, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: asset %q not found", file, name)
+}
+
+// ratio formats the ratio of the stored size to the original one.
+func ratio(stored, size int) string {
+	if size == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", 100*float64(stored)/float64(size))
+}
@@ -5,13 +5,20 @@
 package main
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
-	"time"
 
 	"github.com/rjeczalik/bindata"
 )
@@ -23,46 +30,143 @@ func die(err error) {
 
 var data = string(os.PathSeparator) + "data" + string(os.PathSeparator)
 
-func log(c *bindata.Config, d time.Duration, err error) {
+// dataroot is the data directory of a module, relative to its root.
+var dataroot = "data"
+
+// opts configures the generation in automatic mode.
+var opts bindata.GlobOptions
+
+func log(c *bindata.Config, r *bindata.Report, err error) {
 	prefix := c.Input[0].Path
 	if i := strings.Index(prefix, data); i != -1 {
 		prefix = prefix[i+len(data):]
 	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "fail\t%s\t(%s)\t%.3fs\n\terror: %v\n",
-			prefix, c.Output, d.Seconds(), err)
+			prefix, c.Output, r.Duration.Seconds(), err)
+	} else {
+		fmt.Printf("ok\t%s\t(%s)\t%.3fs\n", prefix, c.Output, r.Duration.Seconds())
+		warn(r)
+	}
+}
+
+// warn prints the warnings of the given report.
+func warn(r *bindata.Report) {
+	for _, w := range r.Warnings {
+		fmt.Fprintf(os.Stderr, "bindata: warning: %s: %s\n", r.Output, w)
+	}
+}
+
+// logFailure logs failed configurations and warnings only, for when
+// the reports are printed as JSON.
+func logFailure(c *bindata.Config, r *bindata.Report, err error) {
+	if err != nil {
+		log(c, r, err)
 	} else {
-		fmt.Printf("ok\t%s\t(%s)\t%.3fs\n", prefix, c.Output, d.Seconds())
+		warn(r)
+	}
+}
+
+// printReport writes the given value as JSON to the standard output.
+func printReport(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "\t")
+	if err := enc.Encode(v); err != nil {
+		die(err)
 	}
 }
 
+func logMinify(name string, before, after int) {
+	fmt.Printf("minify\t%s\t%d -> %d bytes\n", name, before, after)
+}
+
+// logMinifyStderr logs minified assets to stderr, for when the reports
+// are printed as JSON.
+func logMinifyStderr(name string, before, after int) {
+	fmt.Fprintf(os.Stderr, "minify\t%s\t%d -> %d bytes\n", name, before, after)
+}
+
 func copycfg(dst, src *bindata.Config) {
+	dst.Command = src.Command
 	dst.Tags = src.Tags
 	dst.NoMemCopy = src.NoMemCopy
 	dst.NoCompress = src.NoCompress
+	dst.Compact = src.Compact
+	dst.Cache = src.Cache
+	dst.Override = src.Override
+	dst.Encrypt = src.Encrypt
+	dst.Key = src.Key
+	dst.SignKey = src.SignKey
 	dst.Debug = src.Debug
+	dst.Root = src.Root
 	dst.Ignore = src.Ignore
+	dst.Transformers = src.Transformers
+	dst.Templates = src.Templates
+	dst.TextTemplates = src.TextTemplates
+	dst.ScanSecrets = src.ScanSecrets
+	dst.AllowSecrets = src.AllowSecrets
+	dst.MaxAssetSize = src.MaxAssetSize
+	dst.MaxTotalSize = src.MaxTotalSize
+	dst.MaxFileSize = src.MaxFileSize
+	dst.SizeBudgets = src.SizeBudgets
+	dst.BudgetWarnOnly = src.BudgetWarnOnly
 	dst.Fmt = src.Fmt
 }
 
 func main() {
-	c, auto := parseArgs()
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err == errDiffer {
+				os.Exit(1)
+			} else if err != nil {
+				die(err)
+			}
+			return
+		}
+	}
+	c, auto, report := parseArgs()
 	if auto {
-		cfgs, err := bindata.Glob(os.Getenv("GOPATH"))
+		cfgs, err := bindata.GlobModule(".", dataroot)
+		if err == bindata.ErrNoModule {
+			cfgs, err = bindata.Glob(os.Getenv("GOPATH"))
+		}
 		if err != nil {
 			die(err)
 		}
 		for _, cfg := range cfgs {
 			copycfg(cfg, c)
 		}
-		if !bindata.GlobGenerate(cfgs, log) {
+		opts.Log = log
+		if report != "" {
+			opts.Log = logFailure
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		rs, err := bindata.GlobGenerateContext(ctx, cfgs, &opts)
+		stop()
+		done := make([]*bindata.Report, 0, len(rs))
+		for i, r := range rs {
+			if r == nil {
+				fmt.Fprintf(os.Stderr, "skip\t%s\n", cfgs[i].Output)
+			} else {
+				done = append(done, r)
+			}
+		}
+		if report != "" {
+			printReport(done)
+		}
+		if err != nil {
 			os.Exit(1)
 		}
 		return
 	}
-	if err := bindata.Generate(c); err != nil {
+	r, err := bindata.Generate(c)
+	if err != nil {
 		die(err)
 	}
+	warn(r)
+	if report != "" {
+		printReport(r)
+	}
 }
 
 // parseArgs creates a new, filled configuration instance
@@ -70,35 +174,164 @@ func main() {
 //
 // This function exits the program with an error, if
 // any of the command line options are incorrect.
-func parseArgs() (c *bindata.Config, auto bool) {
+func parseArgs() (c *bindata.Config, auto bool, report string) {
 	var version bool
+	var keyfile, signfile, minify, maxasset, maxtotal, maxfile, regen string
 
 	c = bindata.NewConfig()
 
 	flag.Usage = func() {
-		fmt.Printf("Usage: %s [options] <input directories>\n\n", os.Args[0])
+		fmt.Printf("Usage: %s [options] <input directories>\n", os.Args[0])
+		fmt.Printf("       %s -regen <file.go>\n", os.Args[0])
+		fmt.Printf("       %s ls <file.go>\n", os.Args[0])
+		fmt.Printf("       %s cat <file.go> <name>\n", os.Args[0])
+		fmt.Printf("       %s extract <file.go> <dir>\n", os.Args[0])
+		fmt.Printf("       %s diff [-prefix path] <old> <new>\n", os.Args[0])
+		fmt.Printf("       %s append [options] <executable> <input directories>\n\n", os.Args[0])
 		flag.PrintDefaults()
 	}
 
 	flag.BoolVar(&c.Debug, "debug", c.Debug, "Do not embed the assets, but provide the embedding API. Contents will still be loaded from disk.")
+	flag.StringVar(&c.Root, "root", c.Root, "Optional directory debug asset paths are relative to; resolved at runtime against $BINDATA_ROOT, if set.")
 	flag.StringVar(&c.Tags, "tags", c.Tags, "Optional set of build tags to include.")
 	flag.StringVar(&c.Prefix, "prefix", c.Prefix, "Optional path prefix to strip off asset names.")
 	flag.BoolVar(&c.Fmt, "fmt", c.Fmt, "Format generated file with gofmt command.")
-	flag.StringVar(&c.Package, "pkg", c.Package, "Package name to use in the generated code.")
+	flag.StringVar(&c.Package, "pkg", c.Package, "Package name to use in the generated code. Defaults to the package of the Go files in the output directory, or main.")
 	flag.BoolVar(&c.NoMemCopy, "nomemcopy", c.NoMemCopy, "Use a .rodata hack to get rid of unnecessary memcopies. Refer to the documentation to see what implications this carries.")
+	flag.BoolVar(&c.Compact, "compact", c.Compact, "Embed the assets in shorter string literals, raw ones for uncompressed text.")
 	flag.BoolVar(&c.NoCompress, "nocompress", c.NoCompress, "Assets will *not* be GZIP compressed when this flag is specified.")
+	flag.BoolVar(&c.Cache, "cache", c.Cache, "Keep decompressed assets in memory after they are first loaded.")
+	flag.BoolVar(&c.Override, "override", c.Override, "Embed the assets, but look them up first in a directory set at runtime with SetOverrideDir or $BINDATA_OVERRIDE.")
+	flag.StringVar(&keyfile, "encrypt", "", "Optional file with a hex-encoded AES key to encrypt the assets with.")
+	flag.StringVar(&signfile, "sign", "", "Optional file with a hex-encoded ed25519 private key or seed to sign the assets with.")
+	flag.StringVar(&minify, "minify", "", "Optional comma-separated list of asset kinds to minify: "+strings.Join(bindata.MinifyKinds(), ", ")+".")
+	flag.StringVar(&c.Templates, "templates", c.Templates, "Optional glob pattern of template assets to parse with the generated Templates function.")
+	flag.BoolVar(&c.TextTemplates, "texttemplates", c.TextTemplates, "Parse templates with text/template instead of html/template.")
 	flag.StringVar(&c.Output, "o", c.Output, "Optional name of the output file to be generated.")
+	flag.BoolVar(&c.ScanSecrets, "scan", c.ScanSecrets, "Fail if the assets contain credentials like private keys or access tokens.")
+	flag.StringVar(&maxasset, "maxasset", "", "Optional maximum embedded size of a single asset, e.g. 512K or 4M.")
+	flag.StringVar(&maxtotal, "maxtotal", "", "Optional maximum embedded size of all the assets, e.g. 64M.")
+	flag.StringVar(&c.Pack, "pack", c.Pack, "Optional name of a pack file to store the assets in, read at runtime by the generated code.")
+	flag.StringVar(&c.PackPath, "packpath", c.PackPath, "Optional path the generated code opens the pack file at; defaults to the -pack one.")
+	flag.BoolVar(&c.PackAppended, "packexe", c.PackAppended, "Make the generated code read the pack appended to the executable.")
+	flag.StringVar(&maxfile, "maxfile", "", "Optional size of the asset data, e.g. 16M, after which assets are written to bindata_001.go and so on.")
+	flag.BoolVar(&c.BudgetWarnOnly, "budgetwarn", c.BudgetWarnOnly, "Only warn about exceeded size limits instead of failing.")
+	flag.StringVar(&report, "report", "", "Optional format of the generation report to print: json.")
+	flag.StringVar(&dataroot, "dataroot", dataroot, "Data directory of the module, which is mapped onto its packages in automatic mode.")
+	flag.IntVar(&opts.Workers, "j", opts.Workers, "Maximum number of files generated at once in automatic mode. Defaults to the number of CPUs.")
+	flag.BoolVar(&opts.FailFast, "failfast", opts.FailFast, "Stop generating files in automatic mode after the first failure.")
+	flag.DurationVar(&opts.Timeout, "timeout", opts.Timeout, "Optional time limit for generating a single file in automatic mode, e.g. 30s.")
+	flag.StringVar(&regen, "regen", "", "Regenerate the given file with the command recorded in its header.")
 	flag.BoolVar(&version, "version", false, "Displays version information.")
 
 	ignore := make([]string, 0)
 	flag.Var((*AppendSliceValue)(&ignore), "ignore", "Regex pattern to ignore")
 
+	allow := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&allow), "allow", "Regex pattern of files not scanned for secrets")
+
+	budgets := make([]string, 0)
+	flag.Var((*AppendSliceValue)(&budgets), "budget", "Maximum embedded size of the assets matching a pattern, e.g. 'images/**=8M'")
+
 	flag.Parse()
 
+	if regen != "" {
+		dir, args, err := bindata.ReadCommand(regen)
+		if err != nil {
+			die(err)
+		}
+		if err := os.Chdir(dir); err != nil {
+			die(err)
+		}
+		os.Args = append(os.Args[:1], args...)
+		flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+		return parseArgs()
+	}
+
+	c.Command = append([]string{"bindata"}, os.Args[1:]...)
+
 	for _, pattern := range ignore {
 		c.Ignore = append(c.Ignore, regexp.MustCompile(pattern))
 	}
 
+	for _, pattern := range allow {
+		c.AllowSecrets = append(c.AllowSecrets, regexp.MustCompile(pattern))
+	}
+
+	if maxasset != "" {
+		n, err := parseSize(maxasset)
+		if err != nil {
+			die(err)
+		}
+		c.MaxAssetSize = n
+	}
+
+	if maxtotal != "" {
+		n, err := parseSize(maxtotal)
+		if err != nil {
+			die(err)
+		}
+		c.MaxTotalSize = n
+	}
+
+	if maxfile != "" {
+		n, err := parseSize(maxfile)
+		if err != nil {
+			die(err)
+		}
+		c.MaxFileSize = n
+	}
+
+	for _, budget := range budgets {
+		i := strings.LastIndex(budget, "=")
+		if i == -1 {
+			die(fmt.Errorf("invalid budget %q, must be pattern=size", budget))
+		}
+		n, err := parseSize(budget[i+1:])
+		if err != nil {
+			die(err)
+		}
+		c.SizeBudgets = append(c.SizeBudgets, bindata.SizeBudget{Pattern: budget[:i], MaxSize: n})
+	}
+
+	if keyfile != "" {
+		key, err := readKey(keyfile)
+		if err != nil {
+			die(err)
+		}
+		c.Encrypt, c.Key = true, key
+	}
+
+	if signfile != "" {
+		key, err := readKey(signfile)
+		if err != nil {
+			die(err)
+		}
+		if len(key) == ed25519.SeedSize {
+			key = ed25519.NewKeyFromSeed(key)
+		}
+		c.SignKey = key
+	}
+
+	if minify != "" {
+		logf := logMinify
+		if report != "" {
+			logf = logMinifyStderr
+		}
+		t, err := bindata.Minify(strings.Split(minify, ","), logf)
+		if err != nil {
+			die(err)
+		}
+		// Minifiers keep the asset names, so debug builds skip them.
+		if !c.Debug {
+			c.Transformers = append(c.Transformers, t...)
+		}
+	}
+
+	if report != "" && report != "json" {
+		die(fmt.Errorf("unsupported report format %q", report))
+	}
+
 	if version {
 		fmt.Printf("%s\n", Version())
 		os.Exit(0)
@@ -106,6 +339,9 @@ func parseArgs() (c *bindata.Config, auto bool) {
 
 	// No input directories provided, assuming automatic mode.
 	if flag.NArg() == 0 {
+		if c.Pack != "" || c.PackAppended {
+			die(errors.New("pack files are not supported in automatic mode"))
+		}
 		auto = true
 		return
 	}
@@ -119,6 +355,44 @@ func parseArgs() (c *bindata.Config, auto bool) {
 	return
 }
 
+// parseSize parses a size in bytes with an optional K, M or G suffix,
+// e.g. 512K for 512 KiB.
+func parseSize(s string) (int64, error) {
+	if s == "" {
+		return 0, errors.New("missing size")
+	}
+	num, mult := s, int64(1)
+	switch strings.ToUpper(s[len(s)-1:]) {
+	case "K":
+		mult = 1 << 10
+	case "M":
+		mult = 1 << 20
+	case "G":
+		mult = 1 << 30
+	}
+	if mult != 1 {
+		num = s[:len(s)-1]
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return n * mult, nil
+}
+
+// readKey reads the hex-encoded key from the given file.
+func readKey(file string) ([]byte, error) {
+	p, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(p)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid key in %s: %v", file, err)
+	}
+	return key, nil
+}
+
 // parseRecursive determines whether the given path has a recursive indicator and
 // returns a new path with the recursive indicator chopped off if it does.
 //
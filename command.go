@@ -0,0 +1,161 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	generatedLine = "// Code generated by bindata; DO NOT EDIT."
+	commandPrefix = "// Command: "
+	dirPrefix     = "// Directory: "
+)
+
+// ErrNoCommand is returned by ReadCommand for files, which do not record
+// the command they were generated with.
+var ErrNoCommand = errors.New("no generating command recorded")
+
+// writeHeader writes the comment marking the output file as generated and,
+// if configured, the command line generating it together with the working
+// directory relative to the output file.
+func writeHeader(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, "%s\n", generatedLine)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Command) != 0 {
+		_, err = fmt.Fprintf(w, "%s%s\n", commandPrefix, joinArgs(c.Command))
+		if err != nil {
+			return err
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			return err
+		}
+
+		out, err := filepath.Abs(c.Output)
+		if err != nil {
+			return err
+		}
+
+		dir, err := filepath.Rel(filepath.Dir(out), cwd)
+		if err != nil {
+			return err
+		}
+
+		if dir != "." {
+			_, err = fmt.Fprintf(w, "%s%s\n", dirPrefix, joinArgs([]string{filepath.ToSlash(dir)}))
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "\n")
+	return err
+}
+
+// ReadCommand reads the header of a file generated with the Command
+// option set. It returns the working directory the command was run in
+// and its arguments, without the program name.
+func ReadCommand(file string) (dir string, args []string, err error) {
+	fd, err := os.Open(file)
+	if err != nil {
+		return "", nil, err
+	}
+	defer fd.Close()
+
+	dir = "."
+	var cmd []string
+	scanner := bufio.NewScanner(fd)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		switch {
+		case strings.HasPrefix(line, commandPrefix):
+			if cmd, err = splitArgs(line[len(commandPrefix):]); err != nil {
+				return "", nil, fmt.Errorf("%s: %v", file, err)
+			}
+		case strings.HasPrefix(line, dirPrefix):
+			d, err := splitArgs(line[len(dirPrefix):])
+			if err != nil || len(d) != 1 {
+				return "", nil, fmt.Errorf("%s: malformed directory %q", file, line[len(dirPrefix):])
+			}
+			dir = filepath.FromSlash(d[0])
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return "", nil, err
+	}
+
+	if len(cmd) == 0 {
+		return "", nil, fmt.Errorf("%s: %v", file, ErrNoCommand)
+	}
+
+	return filepath.Join(filepath.Dir(file), dir), cmd[1:], nil
+}
+
+// joinArgs joins the arguments with spaces, quoting the ones, which
+// contain spaces or special characters.
+func joinArgs(args []string) string {
+	s := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.IndexFunc(arg, needsQuote) != -1 {
+			arg = strconv.Quote(arg)
+		}
+		s[i] = arg
+	}
+	return strings.Join(s, " ")
+}
+
+func needsQuote(r rune) bool {
+	return r <= ' ' || r == '"' || r == '\\' || r == '\'' || r >= 0x7f
+}
+
+// splitArgs splits a line written by joinArgs into the arguments.
+func splitArgs(line string) ([]string, error) {
+	var args []string
+	for {
+		line = strings.TrimLeft(line, " ")
+		if line == "" {
+			return args, nil
+		}
+		if line[0] != '"' {
+			i := strings.IndexByte(line, ' ')
+			if i == -1 {
+				i = len(line)
+			}
+			args, line = append(args, line[:i]), line[i:]
+			continue
+		}
+		i := 1
+		for i < len(line) && line[i] != '"' {
+			if line[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(line) {
+			return nil, fmt.Errorf("unterminated quoted argument %s", line)
+		}
+		arg, err := strconv.Unquote(line[:i+1])
+		if err != nil {
+			return nil, err
+		}
+		args, line = append(args, arg), line[i+1:]
+	}
+}
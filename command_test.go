@@ -0,0 +1,49 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestJoinSplitArgs(t *testing.T) {
+	cases := []struct {
+		args []string
+		line string
+	}{
+		{[]string{"bindata", "-o", "bindata.go", "data/..."}, `bindata -o bindata.go data/...`},
+		{[]string{"bindata", "-prefix", "my assets", "my assets/..."}, `bindata -prefix "my assets" "my assets/..."`},
+		{[]string{"bindata", "-tags", ""}, `bindata -tags ""`},
+		{[]string{"bindata", `-ignore`, `\.gitignore`}, `bindata -ignore "\\.gitignore"`},
+		{[]string{"bindata", `say "hi"`, `it's`}, `bindata "say \"hi\"" "it's"`},
+		{[]string{"bindata", "données/...", "日本語"}, `bindata "données/..." "日本語"`},
+		{[]string{"bindata", "a\tb", "c\nd", `\`}, `bindata "a\tb" "c\nd" "\\"`},
+		{[]string{"", " ", `""`}, `"" " " "\"\""`},
+	}
+	for i, cas := range cases {
+		line := joinArgs(cas.args)
+		if line != cas.line {
+			t.Errorf("want line=%s; got %s (i=%d)", cas.line, line, i)
+			continue
+		}
+		args, err := splitArgs(line)
+		if err != nil {
+			t.Errorf("want err=nil; got %v (i=%d)", err, i)
+			continue
+		}
+		if !reflect.DeepEqual(args, cas.args) {
+			t.Errorf("want args=%q; got %q (i=%d)", cas.args, args, i)
+		}
+	}
+}
+
+func TestSplitArgsError(t *testing.T) {
+	for i, line := range []string{`bindata "unterminated`, `bindata "trailing\"`, `bindata "\q"`} {
+		if args, err := splitArgs(line); err == nil {
+			t.Errorf("want err!=nil; got args=%q (i=%d)", args, i)
+		}
+	}
+}
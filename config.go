@@ -5,8 +5,10 @@
 package bindata
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 )
@@ -24,9 +26,19 @@ type InputConfig struct {
 
 // Config defines a set of options for the asset conversion.
 type Config struct {
-	// Name of the package to use. Defaults to 'main'.
+	// Name of the package to use. If left empty, it is read from the
+	// package clauses of the Go files already in the output directory,
+	// so the generated file always compiles in its package; test files
+	// are only consulted if there are no other ones. Defaults to 'main'
+	// if the directory holds no Go files.
 	Package string
 
+	// Command is the command line generating the output, e.g. os.Args.
+	// If set, it is recorded in the header of the output file, next to the
+	// "Code generated" comment, together with the working directory, so
+	// the file can be regenerated from it; see ReadCommand.
+	Command []string
+
 	// Tags specify a set of optional build tags, which should be
 	// included in the generated output. The tags are appended to a
 	// `// +build` line in the beginning of the output file
@@ -105,11 +117,68 @@ type Config struct {
 	// 	}
 	NoMemCopy bool
 
+	// Compact embeds the data in shorter string literals: printable ASCII
+	// characters are kept as they are and only the remaining bytes are
+	// escaped, instead of writing every byte as a hex escape. Text assets,
+	// which are not compressed, are written as raw string literals. The
+	// generated files get smaller and compile faster; the API is the same.
+	// Without NoMemCopy the literals are converted to byte slices.
+	Compact bool
+
 	// NoCompress means the assets are /not/ GZIP compressed before being turned
 	// into Go code. The generated function will automatically unzip
 	// the file data when called. Defaults to false.
 	NoCompress bool
 
+	// Cache makes the generated code keep decompressed assets in memory,
+	// so repeated Asset calls for the same name do not inflate the data
+	// again. The cache is safe for concurrent use; its size can be bounded
+	// at runtime with the generated SetAssetCacheLimit function and its
+	// hits and misses inspected with the AssetCache function.
+	//
+	// Slices returned by a cached Asset are shared between callers and
+	// must not be modified. Debug builds cache nothing, but provide the
+	// same API. Defaults to false.
+	Cache bool
+
+	// Override generates a hybrid release build. All assets are embedded
+	// as usual, but the generated code first looks them up in an override
+	// directory on disk, set at runtime with the generated SetOverrideDir
+	// function or the BINDATA_OVERRIDE environment variable. If an asset
+	// file exists there under its name, the disk copy wins.
+	//
+	// This allows patching assets of a deployed program without rebuilding
+	// it. In debug builds SetOverrideDir does nothing. Defaults to false.
+	Override bool
+
+	// Encrypt seals each asset with AES-GCM after it was compressed, so its
+	// content can not be read from the compiled binary. The Key field holds
+	// the AES key, which must be 16, 24 or 32 bytes long.
+	//
+	// The generated code does not embed the key. Instead, it calls a key
+	// provider function, registered at runtime with the generated
+	// SetAssetKeyProvider function, before decrypting an asset. When no key
+	// is available or the asset fails authentication, Asset returns an
+	// *AssetError wrapping ErrAssetKeyMissing or ErrAssetDecrypt respectively.
+	//
+	// Debug builds read the plain files, but provide the same API; their
+	// SetAssetKeyProvider does nothing. Defaults to false.
+	Encrypt bool
+
+	// Key is the AES key used to encrypt the assets, if Encrypt is set.
+	// The keys of the cipher and of the nonces are derived from it with
+	// HKDF-SHA256, so it is never used directly.
+	Key []byte
+
+	// SignKey, if set, signs the SHA-256 digests of the embedded asset data
+	// with ed25519 at generation time. The generated VerifyAssets function
+	// recomputes the digests at runtime and checks them against the
+	// signature with the matching public key, detecting assets which were
+	// patched in the compiled binary.
+	//
+	// In debug builds VerifyAssets always succeeds.
+	SignKey ed25519.PrivateKey
+
 	// Perform a debug build. This generates an asset file, which
 	// loads the asset contents directly from disk at their original
 	// location, instead of embedding the contents in the code.
@@ -121,6 +190,57 @@ type Config struct {
 	// in the code. The default behaviour is Release mode.
 	Debug bool
 
+	// Root makes a debug build relocatable. When set, asset paths are stored
+	// in the generated code relative to this directory rather than as
+	// absolute paths. At runtime they are resolved against the generated
+	// AssetRoot variable, which defaults to Root as given here, or against
+	// the BINDATA_ROOT environment variable, when it is set.
+	//
+	// A relative Root, e.g. ".", is resolved against the working directory
+	// of the program using the assets. Only applies to debug builds.
+	Root string
+
+	// MaxFileSize, if set, limits the size of the asset data written to
+	// a single file in release builds. Once the data in the output file
+	// reaches it, the following assets are written to shards next to it,
+	// e.g. bindata_001.go, bindata_002.go and so on for bindata.go, each
+	// of them filled up to the same size. The table of contents and the
+	// helper functions stay in the output file. Every file holds at least
+	// one asset, so a larger asset gets a file of its own.
+	//
+	// Shards left over from previous runs are removed.
+	MaxFileSize int64
+
+	// Pack, if set, makes a release build store the asset data in a single
+	// pack file at this path instead of embedding it in the generated code,
+	// which keeps the same API and holds a small loader only. At runtime
+	// the loader maps the pack into memory, where the platform supports it,
+	// and reads the assets from the file on demand otherwise. The platform
+	// specific parts of the loader are written next to the output file,
+	// e.g. bindata_mmap.go and bindata_nommap.go for bindata.go.
+	//
+	// This keeps large assets away from the compiler and allows replacing
+	// them without rebuilding the program, as long as the asset names stay
+	// the same. NoMemCopy, Compact and MaxFileSize have no effect on pack
+	// builds, and SignKey is not supported. Debug builds read the assets
+	// from disk, but declare AssetPack and ErrAssetPack as well.
+	Pack string
+
+	// PackPath is the path the generated loader opens the pack at, which
+	// defaults to Pack. It can be changed at runtime with the generated
+	// AssetPack variable or the BINDATA_PACK environment variable.
+	PackPath string
+
+	// PackAppended makes the generated loader read the pack appended to
+	// the running executable, unless a path is set at runtime. Without
+	// Pack, only the loader is generated; see AppendPack.
+	PackAppended bool
+
+	// Workers is the number of assets read, transformed and compressed
+	// at once in release builds. The output does not depend on it.
+	// Defaults to GOMAXPROCS.
+	Workers int
+
 	// Recursively process all assets in the input directory and its
 	// sub directories. This defaults to false, so only files in the
 	// input directory itself are read.
@@ -132,37 +252,87 @@ type Config struct {
 	//
 	// This parameter can be provided multiple times.
 	Ignore []*regexp.Regexp
+
+	// Transformers preprocess the assets matching their patterns before
+	// they are compressed and embedded, e.g. to minify them or convert
+	// their format. They are applied in order and may rename the assets.
+	// Debug builds read the original files as they are, but still run the
+	// transformers at generation time to name the assets the way release
+	// builds do, so a renamed asset is found under the same name in both.
+	// Assets are read concurrently, so the transformers must be safe for
+	// concurrent use.
+	Transformers []TransformConfig
+
+	// Templates is a glob pattern of template assets, e.g.
+	// "templates/**/*.tmpl", as matched by the Transformers patterns.
+	// When set, the generated code gets a Templates function, which parses
+	// all the matching assets into a single template set, each template
+	// named by its asset name. Custom functions are registered with the
+	// generated SetTemplateFuncs function.
+	//
+	// The templates are parsed at generation time as well, so syntax
+	// errors fail the generation rather than the program using them.
+	// A failed generation leaves the previous output file in place.
+	Templates string
+
+	// TextTemplates makes the generated Templates function use the
+	// text/template package instead of html/template.
+	TextTemplates bool
+
+	// ScanSecrets makes Translate scan the assets for credentials before
+	// embedding them: private keys, AWS keys, high-entropy tokens and
+	// .env files. If any are found, generation fails with a SecretError
+	// listing the offending files and lines. Base64 data URIs and
+	// subresource integrity hashes are not taken for tokens, and lines
+	// containing "bindata:allow", e.g. in a comment, are not scanned.
+	ScanSecrets bool
+
+	// AllowSecrets lists patterns of known-safe files, which are not scanned
+	// for secrets, e.g. test fixtures. A pattern is matched against both
+	// the file path and the asset name.
+	AllowSecrets []*regexp.Regexp
+
+	// MaxAssetSize is the maximum size of a single asset in bytes, and
+	// MaxTotalSize the maximum size of all the assets together. Zero means
+	// no limit. The sizes are those of the embedded data, i.e. after
+	// transforming, compressing and encrypting an asset; in debug builds
+	// the source file sizes are checked instead.
+	//
+	// When a limit is exceeded, generation fails with a BudgetError naming
+	// the offending files, and the files generated by a previous run are
+	// left intact.
+	MaxAssetSize int64
+	MaxTotalSize int64
+
+	// SizeBudgets limit the total size of the assets matching their
+	// patterns, e.g. to keep the images within a few megabytes.
+	SizeBudgets []SizeBudget
+
+	// BudgetWarnOnly makes exceeded size limits add warnings to the
+	// generation report rather than fail the generation.
+	BudgetWarnOnly bool
 }
 
 // NewConfig returns a default configuration struct.
 func NewConfig() *Config {
 	c := new(Config)
-	c.Package = "main"
+	c.Package = ""
 	c.NoMemCopy = false
 	c.NoCompress = false
 	c.Debug = false
 	c.Recursive = false
 	c.Output = "./bindata.go"
 	c.Ignore = make([]*regexp.Regexp, 0)
+	c.AllowSecrets = make([]*regexp.Regexp, 0)
 	return c
 }
 
 // validate ensures the config has sane values.
 // Part of which means checking if certain file/directory paths exist.
 func (c *Config) validate() error {
-	if len(c.Package) == 0 {
-		return fmt.Errorf("Missing package name")
-	}
-
-	for _, input := range c.Input {
-		stat, err := os.Lstat(input.Path)
-		if err != nil {
-			return fmt.Errorf("Failed to stat input path '%s': %v", input.Path, err)
-		}
-
-		if !stat.IsDir() {
-			return fmt.Errorf("Input path '%s' is not a directory.", input.Path)
-		}
+	err := c.validateAssets()
+	if err != nil {
+		return err
 	}
 
 	if len(c.Output) == 0 {
@@ -196,5 +366,62 @@ func (c *Config) validate() error {
 		return fmt.Errorf("Output path is a directory.")
 	}
 
+	if len(c.Package) == 0 {
+		name, err := packageName(filepath.Dir(c.Output), c.Output)
+		if err != nil {
+			return fmt.Errorf("Package name: %v", err)
+		}
+		if name == "" {
+			name = "main"
+		}
+		c.Package = name
+	}
+
+	return nil
+}
+
+// validateAssets ensures the options affecting the asset data have sane
+// values and the input directories exist.
+func (c *Config) validateAssets() error {
+	if c.Encrypt && !c.Debug {
+		switch len(c.Key) {
+		case 16, 24, 32:
+		default:
+			return fmt.Errorf("Invalid encryption key size %d, must be 16, 24 or 32 bytes", len(c.Key))
+		}
+	}
+
+	if c.SignKey != nil && len(c.SignKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("Invalid signing key size %d, must be %d bytes", len(c.SignKey), ed25519.PrivateKeySize)
+	}
+
+	if c.packed() {
+		if c.SignKey != nil {
+			return fmt.Errorf("Signing is not supported with a pack file")
+		}
+		c.NoMemCopy, c.Compact = false, false
+	}
+
+	if c.MaxAssetSize < 0 || c.MaxTotalSize < 0 || c.MaxFileSize < 0 {
+		return fmt.Errorf("Invalid negative size limit")
+	}
+
+	for _, b := range c.SizeBudgets {
+		if _, err := path.Match(b.Pattern, ""); err != nil || b.MaxSize < 0 {
+			return fmt.Errorf("Invalid size budget %q: %d bytes", b.Pattern, b.MaxSize)
+		}
+	}
+
+	for _, input := range c.Input {
+		stat, err := os.Lstat(input.Path)
+		if err != nil {
+			return fmt.Errorf("Failed to stat input path '%s': %v", input.Path, err)
+		}
+
+		if !stat.IsDir() {
+			return fmt.Errorf("Input path '%s' is not a directory.", input.Path)
+		}
+	}
+
 	return nil
 }
@@ -6,12 +6,16 @@ package bindata
 
 import (
 	"bufio"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"regexp"
+	"sort"
 	"strings"
+	"time"
 	"unicode"
 )
 
@@ -19,8 +23,13 @@ import (
 // to Go code and writes new files to the output specified
 // in the given configuration.
 func Translate(c *Config) error {
-	var toc []Asset
+	return translate(context.Background(), c, &Report{Output: c.Output})
+}
 
+// translate implements Translate, recording the processed assets
+// in the given report. It stops early with the context error when
+// the context is done.
+func translate(ctx context.Context, c *Config, r *Report) error {
 	// Ensure our configuration has sane values.
 	err := c.validate()
 	if err != nil {
@@ -28,15 +37,25 @@ func Translate(c *Config) error {
 	}
 
 	// Locate all the assets.
-	for _, input := range c.Input {
-		err = findFiles(input.Path, c.Prefix, input.Recursive, &toc, c.Ignore)
+	toc, err := Assets(c)
+	if err != nil {
+		return err
+	}
+
+	// Refuse to embed credentials.
+	if c.ScanSecrets {
+		err = scanSecrets(toc, c.AllowSecrets)
 		if err != nil {
 			return err
 		}
 	}
 
-	// Create output file.
-	fd, err := os.Create(c.Output)
+	// Create output file under a temporary name, so a failure does not
+	// leave a broken file behind.
+	out := &outputFiles{}
+	defer out.remove()
+
+	fd, err := out.create(c.Output)
 	if err != nil {
 		return err
 	}
@@ -45,7 +64,12 @@ func Translate(c *Config) error {
 
 	// Create a buffered writer for better performance.
 	bfd := bufio.NewWriter(fd)
-	defer bfd.Flush()
+
+	// Write header marking the file as generated.
+	err = writeHeader(bfd, c)
+	if err != nil {
+		return err
+	}
 
 	// Write build tags, if applicable.
 	if len(c.Tags) > 0 {
@@ -61,37 +85,150 @@ func Translate(c *Config) error {
 		return err
 	}
 
+	// Write imports.
+	err = writeImports(bfd, c)
+	if err != nil {
+		return err
+	}
+
 	// Write assets.
 	if c.Debug {
-		err = writeDebug(bfd, toc)
+		err = writeDebug(ctx, bfd, c, toc, r)
 	} else {
-		err = writeRelease(bfd, c, toc)
+		err = writeRelease(ctx, bfd, c, toc, r, out)
+	}
+
+	if err != nil {
+		return err
 	}
 
+	// Enforce size limits.
+	err = checkBudgets(c, r)
 	if err != nil {
 		return err
 	}
 
+	// Write template set, if applicable.
+	if c.Templates != "" {
+		err = writeTemplates(bfd, c, toc)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Write table of contents
-	return writeTOC(bfd, toc)
+	err = writeTOC(bfd, c, toc)
+	if err != nil {
+		return err
+	}
+
+	// Flush and close explicitly, so write errors are not lost.
+	err = bfd.Flush()
+	if err != nil {
+		return err
+	}
+
+	err = fd.Close()
+	if err != nil {
+		return err
+	}
+
+	// Replace the output, shards and pack of previous runs.
+	err = out.commit()
+	if err != nil {
+		return err
+	}
+
+	// Remove shards and loader files left over from previous runs.
+	err = cleanShards(c.Output, len(r.Shards))
+	if err != nil {
+		return err
+	}
+
+	if !c.packed() {
+		return cleanPackFiles(c.Output)
+	}
+
+	return nil
+}
+
+// writeImports writes the import declaration of the generated code.
+func writeImports(w io.Writer, c *Config) error {
+	var pkgs []string
+	if c.Debug {
+		pkgs = debugImports(c)
+	} else {
+		pkgs = releaseImports(c)
+	}
+
+	if c.Templates != "" {
+		pkgs = append(pkgs, templatePackage(c))
+	}
+
+	sort.Strings(pkgs)
+
+	_, err := fmt.Fprintf(w, "import (\n")
+	if err != nil {
+		return err
+	}
+
+	for i, pkg := range pkgs {
+		if i > 0 && pkgs[i-1] == pkg {
+			continue
+		}
+		_, err = fmt.Fprintf(w, "\t%q\n", pkg)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, ")\n\n")
+	return err
 }
 
 // Generate translates configured assets into Go code and performs additional
-// postprocessing if configured.
-func Generate(c *Config) (err error) {
-	if err = Translate(c); err != nil {
+// postprocessing if configured. It returns a report describing the embedded
+// assets; on failure the report covers the assets processed so far.
+func Generate(c *Config) (*Report, error) {
+	return GenerateContext(context.Background(), c)
+}
+
+// GenerateContext is like Generate, but it gives up with the context error
+// once the context is done.
+func GenerateContext(ctx context.Context, c *Config) (r *Report, err error) {
+	begin := time.Now()
+	r = &Report{Output: c.Output}
+	defer func() {
+		r.Duration = time.Since(begin)
+	}()
+
+	if err = translate(ctx, c, r); err != nil {
 		return
 	}
 
 	// Format generated file with gofmt if applicable.
 	if c.Fmt {
-		if err = exec.Command("gofmt", "-w", "-s", c.Output).Run(); err != nil {
+		args := append([]string{"-w", "-s", c.Output}, r.Shards...)
+		if err = exec.CommandContext(ctx, "gofmt", args...).Run(); err != nil {
 			return
 		}
 	}
 	return
 }
 
+// Assets returns the assets found in the input directories of the given
+// configuration, i.e. the ones Translate would embed, without reading them.
+func Assets(c *Config) ([]Asset, error) {
+	var toc []Asset
+	for _, input := range c.Input {
+		err := findFiles(input.Path, c.Prefix, input.Recursive, &toc, c.Ignore)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return toc, nil
+}
+
 // findFiles recursively finds all the file paths in the given directory tree.
 // They are added to the given map as keys. Values will be safe function names
 // for each file, which will be used when generating the output code.
@@ -5,61 +5,223 @@
 package bindata
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
 )
 
-// writeDebug writes the debug code file.
-func writeDebug(w io.Writer, toc []Asset) error {
-	err := writeDebugHeader(w)
+// writeDebug writes the debug code file and records the referenced
+// assets in the given report.
+func writeDebug(ctx context.Context, w io.Writer, c *Config, toc []Asset, r *Report) error {
+	var root string
+	if c.Root != "" {
+		var err error
+		if root, err = filepath.Abs(c.Root); err != nil {
+			return err
+		}
+	}
+
+	err := writeDebugHeader(w, c)
 	if err != nil {
 		return err
 	}
 
 	for i := range toc {
-		err = writeDebugAsset(w, &toc[i])
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		begin := time.Now()
+		err = transformName(c, &toc[i])
+		if err != nil {
+			return err
+		}
+
+		if isTemplate(c, &toc[i]) {
+			err = checkTemplateFile(&toc[i])
+			if err != nil {
+				return err
+			}
+		}
+
+		err = writeDebugAsset(w, root, &toc[i])
 		if err != nil {
 			return err
 		}
+
+		fi, err := os.Stat(toc[i].Path)
+		if err != nil {
+			return err
+		}
+
+		r.add(AssetReport{
+			Path:     toc[i].Path,
+			Name:     toc[i].Name,
+			Func:     toc[i].Func,
+			Size:     fi.Size(),
+			Duration: time.Since(begin),
+		})
+	}
+
+	if c.Cache {
+		err = writeDebugCache(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Override {
+		err = writeDebugOverride(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Encrypt {
+		err = writeDebugEncrypted(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Pack != "" || c.PackAppended {
+		err = writeDebugPack(w, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.SignKey != nil {
+		return writeDebugVerify(w)
 	}
 
 	return nil
 }
 
+// transformName runs the transformers over the given asset to rename it
+// the way a release build does. The transformed content is discarded,
+// debug code reads the original file.
+func transformName(c *Config, asset *Asset) error {
+	if len(c.Transformers) == 0 {
+		return nil
+	}
+
+	fd, err := os.Open(asset.Path)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	r, err := transform(c, asset, fd)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(ioutil.Discard, r)
+	return err
+}
+
+// debugImports returns the packages imported by the debug code.
+func debugImports(c *Config) []string {
+	pkgs := []string{"errors", "fmt", "io", "io/ioutil", "os", "strings"}
+	if c.Root != "" {
+		pkgs = append(pkgs, "path/filepath")
+	}
+	if c.SignKey != nil {
+		pkgs = append(pkgs, "crypto/ed25519")
+	}
+	return pkgs
+}
+
 // writeDebugHeader writes output file headers.
 // This targets debug builds.
-func writeDebugHeader(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `import (
-	"fmt"
-	"io/ioutil"
-	"strings"
-)
+func writeDebugHeader(w io.Writer, c *Config) error {
+	if c.Root != "" {
+		err := writeDebugRoot(w, c)
+		if err != nil {
+			return err
+		}
+	}
 
-// bindata_read reads the given file from disk. It returns an error on failure.
+	_, err := fmt.Fprintf(w, `// bindata_read reads the given file from disk. It returns an error on failure.
 func bindata_read(path, name string) ([]byte, error) {
 	buf, err := ioutil.ReadFile(path)
 	if err != nil {
-		err = fmt.Errorf("Error reading asset %%s at %%s: %%v", name, path, err)
+		return nil, &AssetError{Name: name, Err: err}
+	}
+	return buf, nil
+}
+
+// bindata_reader opens the given file from disk. It returns an error on failure.
+func bindata_reader(path, name string) (io.ReadCloser, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
 	}
-	return buf, err
+	return fd, nil
 }
 
 `)
 	return err
 }
 
+// writeDebugRoot writes the variable and function resolving relative
+// asset paths at runtime.
+func writeDebugRoot(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, `// AssetRoot is the directory, which asset paths are relative to.
+// It is overridden by the BINDATA_ROOT environment variable, if set.
+var AssetRoot = %q
+
+// bindata_path resolves the given asset path against the asset root.
+func bindata_path(path string) string {
+	root := AssetRoot
+	if env := os.Getenv("BINDATA_ROOT"); env != "" {
+		root = env
+	}
+	return filepath.Join(root, filepath.FromSlash(path))
+}
+
+`, filepath.ToSlash(c.Root))
+	return err
+}
+
 // writeDebugAsset write a debug entry for the given asset.
-// A debug entry is simply a function which reads the asset from
-// the original file (e.g.: from disk).
-func writeDebugAsset(w io.Writer, asset *Asset) error {
+// A debug entry is simply a pair of functions which read and stream
+// the asset from the original file (e.g.: from disk).
+//
+// If root is not empty, the path of the asset is written relative to it
+// and resolved when the asset is read.
+func writeDebugAsset(w io.Writer, root string, asset *Asset) error {
+	path := fmt.Sprintf("%q", asset.Path)
+	if root != "" {
+		rel, err := filepath.Rel(root, asset.Path)
+		if err != nil {
+			return err
+		}
+		path = fmt.Sprintf("bindata_path(%q)", filepath.ToSlash(rel))
+	}
+
 	_, err := fmt.Fprintf(w, `// %s reads file data from disk. It returns an error on failure.
 func %s() ([]byte, error) {
 	return bindata_read(
+		%s,
 		%q,
+	)
+}
+
+// %s_reader opens file data on disk. It returns an error on failure.
+func %s_reader() (io.ReadCloser, error) {
+	return bindata_reader(
+		%s,
 		%q,
 	)
 }
 
-`, asset.Func, asset.Func, asset.Path, asset.Name)
+`, asset.Func, asset.Func, path, asset.Name, asset.Func, asset.Func, path, asset.Name)
 	return err
 }
@@ -0,0 +1,47 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDebugTransformName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c := NewConfig()
+	c.Debug = true
+	c.Package = "main"
+	c.Prefix = "testdata"
+	c.Output = filepath.Join(dir, "bindata.go")
+	c.Input = []InputConfig{{Path: "testdata/in", Recursive: true}}
+	c.Transformers = []TransformConfig{{
+		Pattern: "in/a/*",
+		Transformer: TransformerFunc(func(name string, r io.Reader) (string, io.Reader, error) {
+			return name + ".renamed", r, nil
+		}),
+	}}
+	if err := Translate(c); err != nil {
+		t.Fatalf("want err=nil; got %v", err)
+	}
+	p, err := ioutil.ReadFile(c.Output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{`"in/a/test.asset.renamed"`, "func in_a_test_asset_renamed()", `"in/b/test.asset"`} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("want %s in the debug code", s)
+		}
+	}
+}
@@ -12,6 +12,18 @@ which can be specified in the Config struct, which must be passed into
 the Translate() call.
 
 
+Automatic conversion within a Go module
+
+GlobModule is the module-aware counterpart of Glob. It looks up the go.mod
+file enclosing a directory and maps the directories of the module's data
+root, e.g. "data", onto its package directories, using the longest matching
+path. The package name of each generated file is read from the Go files
+already in the target directory. The command line tool uses it in automatic
+mode whenever it runs within a module, and falls back to $GOPATH otherwise.
+
+	cfgs, err := bindata.GlobModule(".", "data")
+
+
 Automatic conversion within $GOPATH workspace
 
 When no input files nor directories are provided via command line flags,
@@ -64,6 +76,99 @@ will create two assets files under he following paths:
 Running go-bindata in this mode will ignore any values passed by -o, -pkg and
 -prefix flags.
 
+The configurations returned by Glob and GlobModule are generated concurrently
+with GlobGenerateContext. GlobOptions bound the number of workers, limit the
+time spent on each configuration and choose whether to stop at the first
+failure. Every failed or skipped configuration is listed in the returned
+GlobError:
+
+	reports, err := bindata.GlobGenerateContext(ctx, cfgs, &bindata.GlobOptions{
+		Workers:  4,
+		FailFast: true,
+		Timeout:  time.Minute,
+	})
+
+Generated file header
+
+Every generated file starts with the standard "Code generated by bindata;
+DO NOT EDIT." comment, so linters and tools recognise it. If the Command
+option is set, the header also records the command line and the working
+directory it was run in, relative to the file. ReadCommand reads them back
+for regenerating the file.
+
+Package name
+
+When the Package option is left empty, the package name is read from the
+package clauses of the Go files already in the output directory, skipping
+files with an "ignore" build constraint, so the generated file compiles in
+its package even for directories like go-foo. If the directory holds test
+files only, the name of the package under test is used. Conflicting clauses
+fail the generation; a directory without Go files gets package main.
+
+Generation reports
+
+Generate returns a Report next to the error. It records, for each asset,
+the source path, the name and function, the size of the source file and of
+the embedded data, their ratio, the SHA-256 digest of the source and the
+time spent on it, together with the totals for the whole output file. The
+report has JSON tags, so it can be fed to a dashboard tracking asset sizes:
+
+	r, err := bindata.Generate(c)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%s: %d -> %d bytes\n", r.Output, r.Size, r.Stored)
+
+Size budgets
+
+MaxAssetSize and MaxTotalSize limit the embedded size of a single asset and
+of all the assets together, and SizeBudgets the total size of the assets
+matching a pattern. When a limit is exceeded, generation fails with a
+BudgetError naming the offending files, largest first. With BudgetWarnOnly
+set, the violations are added to the Warnings of the report instead.
+
+	c.MaxAssetSize = 4 << 20
+	c.SizeBudgets = []bindata.SizeBudget{{Pattern: "images/*", MaxSize: 16 << 20}}
+
+Secret scanning
+
+The ScanSecrets option makes Translate check the assets for credentials,
+like private keys, AWS keys, high-entropy tokens and .env files, before
+embedding them. If any are found, it returns a SecretError with the file
+and line of each one. Files matching the AllowSecrets patterns are skipped,
+and so are single lines containing a "bindata:allow" marker. Base64 data
+URIs and subresource integrity hashes do not count as tokens.
+
+Transforming assets
+
+The Transformers option registers Transformer implementations for glob
+patterns of asset names, where a "**" element matches any number of
+directories. Each matching asset passes through them after it is read and
+before it is compressed, which makes them the hook point for minification,
+template precompilation or format conversion. A transformer may also
+rename the asset:
+
+	c.Transformers = []bindata.TransformConfig{{
+		Pattern: "styles/*.scss",
+		Transformer: bindata.TransformerFunc(func(name string, r io.Reader) (string, io.Reader, error) {
+			css, err := compileSass(r)
+			return strings.TrimSuffix(name, ".scss") + ".css", css, err
+		}),
+	}}
+
+Debug builds serve the original files, though under the names given by the
+transformers, so renamed assets are found in both builds alike.
+
+The Minify function returns transformers minifying CSS, HTML, JavaScript,
+JSON and SVG assets, matched by their file extensions.
+
+Templates
+
+The Templates option generates a Templates function, which parses all assets
+matching the given pattern into a single html/template, or text/template if
+TextTemplates is set, template set. The templates are validated at generation
+time, so syntax errors fail Translate.
+
 Debug vs Release builds
 
 When used with the `Debug` option, the generated code does not actually include
@@ -84,6 +189,77 @@ refresh in the browser to see those changes. Embedding the assets with the
 ready for deployment, just re-invoke `go-bindata` without the `-debug` flag.
 It will now embed the latest version of the assets.
 
+The Root option makes a debug build relocatable, so it can be shared between
+machines or used inside containers. Asset paths are then stored relative to
+Root and resolved at runtime against the generated AssetRoot variable or the
+BINDATA_ROOT environment variable, if set.
+
+
+Hybrid builds
+
+The Override option generates a release build, which looks assets up in an
+override directory on disk before falling back to the embedded data. The
+directory is set at runtime with the generated SetOverrideDir function or the
+BINDATA_OVERRIDE environment variable.
+
+
+Encrypted assets
+
+The Encrypt option seals each compressed asset with AES-GCM using the given
+Key. The key is not embedded in the generated code, which instead calls
+a provider function registered at runtime with SetAssetKeyProvider. The
+cipher key and the key deriving the nonces are derived from it with HKDF.
+
+
+Signed assets
+
+The SignKey option signs the digests of the embedded assets with ed25519.
+The generated VerifyAssets function checks them against the signature with
+the matching public key, detecting embedded data patched in the binary.
+
+
+Parallel generation
+
+Release builds read, transform, compress and encrypt the assets on up to
+Workers goroutines at once, GOMAXPROCS by default, while the generated code
+is still written in the order of the assets, so the output is the same no
+matter how many workers are used. Custom transformers must therefore be safe
+for concurrent use.
+
+Compact literals
+
+By default every embedded byte is written as a hex escape, which makes the
+generated source four to six times larger than the assets. With the Compact
+option printable ASCII characters are kept as they are, and uncompressed
+text assets are written as raw string literals, so the generated files get
+smaller and compile faster. The generated API does not change.
+
+Splitting large outputs
+
+A single generated file with hundreds of megabytes of literals slows down
+the compiler and editors. MaxFileSize limits the size of the asset data in
+each file: once the output file holds that much, the following assets are
+written to shards next to it, e.g. bindata_001.go and bindata_002.go for
+bindata.go. The table of contents stays in the output file, and shards left
+over from previous runs are removed. ParseFile reads the shards as well.
+
+Pack files
+
+Very large assets need not go through the compiler at all. With the Pack
+option a release build writes the asset data into a single pack file and
+generates a small loader with the same API instead. At runtime the loader
+opens the pack at the path of the generated AssetPack variable, PackPath
+by default, or at $BINDATA_PACK, and maps it into memory where the platform
+supports it; elsewhere the assets are read from the file on demand. The
+platform specific parts of the loader go to bindata_mmap.go and
+bindata_nommap.go next to bindata.go.
+
+A pack ends with its index and a trailer, so it can be appended to the
+executable as well; with PackAppended the loader looks for it there.
+AppendPack appends the pack to an already built executable, replacing the
+one appended before.
+The assets of a pack build can be replaced without recompiling, as long
+as their names stay the same.
 
 Lower memory footprint
 
@@ -145,6 +321,16 @@ even increase the size of the data.
 The default behaviour of the program is to use compression.
 
 
+Caching assets
+
+The Cache option makes the generated code keep decompressed assets in memory,
+so the data is inflated once instead of on every Asset call. The cache is
+unbounded unless limited at runtime with the generated SetAssetCacheLimit
+function, and the generated AssetCache function reports its hits, misses and
+size. Slices returned from a cached Asset call are shared and must not be
+modified. Debug builds ignore this option.
+
+
 Path prefix stripping
 
 The keys used in the `_bindata` map are the same as the input file name
@@ -0,0 +1,213 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+	"io"
+)
+
+// encrypt seals the given asset data with AES-GCM. The name of the asset
+// is used as additional authenticated data, so an encrypted asset can not be
+// swapped for another one. The nonce is prepended to the returned data.
+//
+// The nonce is derived from the key, the name and the data, which keeps the
+// output reproducible; it repeats only for identical assets. The cipher and
+// the nonce use separate keys derived from the given one.
+func encrypt(key []byte, name string, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(deriveKey(key, encryptLabel, len(key)))
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	mac := hmac.New(sha256.New, deriveKey(key, nonceLabel, sha256.Size))
+	io.WriteString(mac, name)
+	mac.Write([]byte{0})
+	mac.Write(data)
+	nonce := mac.Sum(nil)[:gcm.NonceSize()]
+
+	return gcm.Seal(nonce, nonce, data, []byte(name)), nil
+}
+
+// Labels of the keys derived from the master key.
+const (
+	encryptLabel = "bindata encryption key"
+	nonceLabel   = "bindata nonce key"
+)
+
+// deriveKey derives a key of the given size, up to sha256.Size bytes,
+// from the master key with HKDF-SHA256 (RFC 5869), using the label as
+// the info string. The generated bindata_derive_key mirrors it.
+func deriveKey(key []byte, label string, size int) []byte {
+	mac := hmac.New(sha256.New, make([]byte, sha256.Size))
+	mac.Write(key)
+	mac = hmac.New(sha256.New, mac.Sum(nil))
+	io.WriteString(mac, label)
+	mac.Write([]byte{1})
+	return mac.Sum(nil)[:size]
+}
+
+// header_encrypted writes output file headers for encrypted assets.
+// This targets release builds.
+func header_encrypted(w io.Writer, c *Config) error {
+	typ, data := "[]byte", "data"
+	if c.NoMemCopy {
+		typ, data = "string", "[]byte(data)"
+	}
+
+	read := `return b, nil`
+	reader := `return ioutil.NopCloser(bytes.NewReader(b)), nil`
+	if !c.NoCompress {
+		read = `gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	var buf bytes.Buffer
+	_, err = io.Copy(&buf, gz)
+	gz.Close()
+
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	return buf.Bytes(), nil`
+		reader = `gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	return gz, nil`
+	}
+
+	_, err := fmt.Fprintf(w, `// ErrAssetKeyMissing is the error wrapped by an *AssetError when an asset
+// is read before a key provider was registered, or the provider returned
+// no key.
+var ErrAssetKeyMissing = errors.New("asset decryption key missing")
+
+// ErrAssetDecrypt is the error wrapped by an *AssetError when an asset
+// could not be decrypted, e.g. because of a wrong key or tampered data.
+var ErrAssetDecrypt = errors.New("asset decryption failed")
+
+var _bindata_key struct {
+	sync.RWMutex
+	provider func() ([]byte, error)
+}
+
+// SetAssetKeyProvider registers the function providing the AES key, which
+// the assets are decrypted with. It is called each time an asset is read.
+func SetAssetKeyProvider(provider func() ([]byte, error)) {
+	_bindata_key.Lock()
+	_bindata_key.provider = provider
+	_bindata_key.Unlock()
+}
+
+// bindata_decrypt authenticates and decrypts the asset data with the key
+// given by the registered key provider.
+func bindata_decrypt(data []byte, name string) ([]byte, error) {
+	_bindata_key.RLock()
+	provider := _bindata_key.provider
+	_bindata_key.RUnlock()
+
+	if provider == nil {
+		return nil, &AssetError{Name: name, Err: ErrAssetKeyMissing}
+	}
+
+	key, err := provider()
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+	if len(key) == 0 {
+		return nil, &AssetError{Name: name, Err: ErrAssetKeyMissing}
+	}
+	if n := len(key); n != 16 && n != 24 && n != 32 {
+		return nil, &AssetError{Name: name, Err: aes.KeySizeError(n)}
+	}
+
+	block, err := aes.NewCipher(bindata_derive_key(key, %q, len(key)))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	if len(data) < gcm.NonceSize() {
+		return nil, &AssetError{Name: name, Err: ErrAssetDecrypt}
+	}
+
+	n := gcm.NonceSize()
+	b, err := gcm.Open(nil, data[:n], data[n:], []byte(name))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: ErrAssetDecrypt}
+	}
+
+	return b, nil
+}
+
+// bindata_derive_key derives a key of the given size from the master key
+// with HKDF-SHA256, using the label as the info string.
+func bindata_derive_key(key []byte, label string, size int) []byte {
+	mac := hmac.New(sha256.New, make([]byte, sha256.Size))
+	mac.Write(key)
+	mac = hmac.New(sha256.New, mac.Sum(nil))
+	io.WriteString(mac, label)
+	mac.Write([]byte{1})
+	return mac.Sum(nil)[:size]
+}
+
+func bindata_read(data %s, name string) ([]byte, error) {
+	b, err := bindata_decrypt(%s, name)
+	if err != nil {
+		return nil, err
+	}
+
+	%s
+}
+
+// bindata_reader returns a reader of the decrypted asset data.
+func bindata_reader(data %s, name string) (io.ReadCloser, error) {
+	b, err := bindata_decrypt(%s, name)
+	if err != nil {
+		return nil, err
+	}
+
+	%s
+}
+
+`, encryptLabel, typ, data, read, typ, data, reader)
+	return err
+}
+
+// writeDebugEncrypted writes the decryption API for debug builds, which
+// read the plain assets from disk.
+func writeDebugEncrypted(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// ErrAssetKeyMissing is the error wrapped by an *AssetError when an asset
+// is read before a key provider was registered, or the provider returned
+// no key. Debug builds never return it.
+var ErrAssetKeyMissing = errors.New("asset decryption key missing")
+
+// ErrAssetDecrypt is the error wrapped by an *AssetError when an asset
+// could not be decrypted. Debug builds never return it.
+var ErrAssetDecrypt = errors.New("asset decryption failed")
+
+// SetAssetKeyProvider does nothing in debug builds, which read the assets
+// unencrypted from disk.
+func SetAssetKeyProvider(provider func() ([]byte, error)) {}
+
+`)
+	return err
+}
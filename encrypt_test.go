@@ -0,0 +1,60 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/hex"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// RFC 5869, test case 3: no salt, no info.
+	key := deriveKey(bytes.Repeat([]byte{0x0b}, 22), "", 32)
+	want := "8da4e775a563c18f715f802a063c5a31b8a11f5c5ee1879ec3454e5f3c738d2d"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("want key=%s; got %s", want, got)
+	}
+
+	master := bytes.Repeat([]byte{1}, 32)
+	enc, nonce := deriveKey(master, encryptLabel, 32), deriveKey(master, nonceLabel, 32)
+	if bytes.Equal(enc, nonce) || bytes.Equal(enc, master) || bytes.Equal(nonce, master) {
+		t.Error("want the derived keys to differ from each other and the master key")
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	data := []byte("// sample file\n")
+	for _, size := range []int{16, 24, 32} {
+		key := bytes.Repeat([]byte{1}, size)
+		p, err := encrypt(key, "a/test.asset", data)
+		if err != nil {
+			t.Errorf("want err=nil; got %v (size=%d)", err, size)
+			continue
+		}
+		q, err := encrypt(key, "a/test.asset", data)
+		if err != nil || !bytes.Equal(p, q) {
+			t.Errorf("want reproducible output; got err=%v (size=%d)", err, size)
+		}
+		block, err := aes.NewCipher(deriveKey(key, encryptLabel, size))
+		if err != nil {
+			t.Fatal(err)
+		}
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n := gcm.NonceSize()
+		plain, err := gcm.Open(nil, p[:n], p[n:], []byte("a/test.asset"))
+		if err != nil || !bytes.Equal(plain, data) {
+			t.Errorf("want data=%q; got %q, err=%v (size=%d)", data, plain, err, size)
+		}
+		if _, err := gcm.Open(nil, p[:n], p[n:], []byte("b/test.asset")); err == nil {
+			t.Errorf("want err!=nil for a swapped asset name (size=%d)", size)
+		}
+	}
+}
@@ -5,11 +5,14 @@
 package bindata
 
 import (
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/rjeczalik/fs/fsutil"
@@ -87,8 +90,12 @@ func Glob(list string) ([]*Config, error) {
 		output := filepath.Join(inout.gopath, "src", inout.dir, "bindata.go")
 		// TODO(rjeczalik): ignore input if max(ModTime in [input/...]) > ModTime(output)
 		if countdir(input) > 0 {
+			name, err := globPackage(output)
+			if err != nil {
+				return nil, err
+			}
 			cfg := NewConfig()
-			cfg.Package = filepath.Base(inout.dir)
+			cfg.Package = name
 			cfg.Prefix = filepath.Join(inout.gopath, "data", inout.dir)
 			cfg.Output = output
 			cfg.Input = []InputConfig{{Path: input, Recursive: true}}
@@ -102,31 +109,144 @@ func Glob(list string) ([]*Config, error) {
 }
 
 // GlobGenerate runs Generate concurrently over cfgs configuration list.
-// It logs execution time and eventual errors via user-provided log function.
+// It logs the report and eventual errors via user-provided log function.
 // It returns true when all executions of Generate were successful,
 // false otherwise.
-func GlobGenerate(cfgs []*Config, log func(*Config, time.Duration, error)) bool {
-	ch, ret, ok := make(chan *Config, len(cfgs)), make(chan error), true
-	for _, cfg := range cfgs {
-		ch <- cfg
+func GlobGenerate(cfgs []*Config, log func(*Config, *Report, error)) bool {
+	_, err := GlobGenerateContext(context.Background(), cfgs, &GlobOptions{Log: log})
+	return err == nil
+}
+
+// GlobOptions configures GlobGenerateContext.
+type GlobOptions struct {
+	// Workers is the maximum number of configurations generated at once.
+	// It defaults to GOMAXPROCS.
+	Workers int
+
+	// FailFast stops the generation after the first failure: the running
+	// configurations are cancelled and the pending ones are not started.
+	// By default the remaining configurations are generated regardless.
+	FailFast bool
+
+	// Timeout limits the time spent generating a single configuration;
+	// zero means no limit.
+	Timeout time.Duration
+
+	// Log, if not nil, is called with the report and error of each
+	// configuration, once it was generated. It may be called concurrently.
+	Log func(*Config, *Report, error)
+}
+
+// ConfigError records the failure of a single configuration.
+type ConfigError struct {
+	Config *Config
+	Err    error
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Config.Output, e.Err)
+}
+
+func (e *ConfigError) Unwrap() error {
+	return e.Err
+}
+
+// GlobError is returned by GlobGenerateContext, listing every configuration
+// which failed or was not generated at all, in the order of the configuration
+// list.
+type GlobError []*ConfigError
+
+func (e GlobError) Error() string {
+	s := make([]string, 0, len(e)+1)
+	s = append(s, fmt.Sprintf("Failed to generate %d file(s):", len(e)))
+	for _, err := range e {
+		s = append(s, "\t"+err.Error())
+	}
+	return strings.Join(s, "\n")
+}
+
+func (e GlobError) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
 	}
-	defer close(ch)
-	for n := min(runtime.GOMAXPROCS(-1), len(cfgs)); n > 0; n-- {
+	return errs
+}
+
+// GlobGenerateContext runs GenerateContext concurrently over cfgs configuration
+// list, as configured by opts, which may be nil. It returns the reports in
+// the order of the configuration list; the report of a configuration, which
+// was not started because the context was done or a previous one failed
+// with FailFast set, is nil.
+//
+// If any configuration was not generated successfully, a GlobError
+// is returned.
+func GlobGenerateContext(ctx context.Context, cfgs []*Config, opts *GlobOptions) ([]*Report, error) {
+	if opts == nil {
+		opts = &GlobOptions{}
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(-1)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		jobs    = make(chan int)
+		reports = make([]*Report, len(cfgs))
+		errs    = make([]error, len(cfgs))
+	)
+	for n := min(workers, len(cfgs)); n > 0; n-- {
+		wg.Add(1)
 		go func() {
-			for c := range ch {
-				begin := time.Now()
-				err := Generate(c)
-				log(c, time.Now().Sub(begin), err)
-				ret <- err
+			defer wg.Done()
+			for i := range jobs {
+				reports[i], errs[i] = generate(ctx, cfgs[i], opts.Timeout)
+				if opts.Log != nil {
+					opts.Log(cfgs[i], reports[i], errs[i])
+				}
+				if errs[i] != nil && opts.FailFast {
+					cancel()
+				}
 			}
 		}()
 	}
-	for i := 0; i < len(cfgs); i++ {
-		if err := <-ret; err != nil {
-			if err != nil {
-				ok = false
-			}
+	started := 0
+LOOP:
+	for ; started < len(cfgs); started++ {
+		select {
+		case jobs <- started:
+		case <-ctx.Done():
+			break LOOP
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	var e GlobError
+	for i := range cfgs {
+		if i >= started {
+			errs[i] = ctx.Err()
+		}
+		if errs[i] != nil {
+			e = append(e, &ConfigError{Config: cfgs[i], Err: errs[i]})
 		}
 	}
-	return ok
+	if len(e) != 0 {
+		return reports, e
+	}
+	return reports, nil
+}
+
+// generate runs GenerateContext, limiting it with the given timeout.
+func generate(ctx context.Context, c *Config, timeout time.Duration) (*Report, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return GenerateContext(ctx, c)
 }
@@ -0,0 +1,268 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"sort"
+	"strconv"
+)
+
+// EmbeddedAsset describes an asset embedded in a generated file.
+type EmbeddedAsset struct {
+	Name       string // Key used in TOC -- name by which asset is referenced.
+	Func       string // Function name for the procedure returning the asset contents.
+	Data       []byte // Data as embedded, i.e. compressed or encrypted if configured.
+	Compressed bool   // Whether Data is gzip compressed.
+	Encrypted  bool   // Whether Data is encrypted.
+}
+
+// ErrEncrypted is returned by EmbeddedAsset.Content for encrypted assets.
+var ErrEncrypted = errors.New("asset is encrypted")
+
+// Content returns the original content of the asset.
+func (a *EmbeddedAsset) Content() ([]byte, error) {
+	if a.Encrypted {
+		return nil, ErrEncrypted
+	}
+	if !a.Compressed {
+		return a.Data, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(a.Data))
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", a.Name, err)
+	}
+	defer gz.Close()
+	p, err := ioutil.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("Read %q: %v", a.Name, err)
+	}
+	return p, nil
+}
+
+// ParseFile parses a Go file generated by Translate and returns the assets
+// embedded in it and its shards, sorted by name. Files generated by earlier
+// versions of bindata, with a map of plain functions as the table of
+// contents and the data returned by the functions, are read as well.
+// Debug builds embed no data and are reported as an error.
+func ParseFile(file string) ([]*EmbeddedAsset, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), file, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	var compressed, encrypted bool
+	for _, imp := range f.Imports {
+		if imp.Path.Value == `"compress/gzip"` {
+			compressed = true
+		}
+	}
+
+	vars := make(map[string]ast.Expr)
+	funcs := make(map[string]*ast.FuncDecl)
+	var toc *ast.CompositeLit
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name == "bindata_decrypt" {
+				encrypted = true
+			}
+			if decl.Recv == nil {
+				funcs[decl.Name.Name] = decl
+			}
+		case *ast.GenDecl:
+			if decl.Tok != token.VAR {
+				continue
+			}
+			addVars(vars, decl)
+		}
+	}
+
+	if v, ok := vars["_bindata"]; ok {
+		toc, _ = v.(*ast.CompositeLit)
+	}
+
+	if toc == nil {
+		return nil, fmt.Errorf("%s: no table of contents found, not a bindata file", file)
+	}
+
+	if isDebugRead(funcs["bindata_read"]) {
+		return nil, fmt.Errorf("%s: debug build, no embedded data", file)
+	}
+
+	// Assets spilled into shards are defined there.
+	shards, err := shardFiles(file)
+	if err != nil {
+		return nil, err
+	}
+	for _, shard := range shards {
+		f, err := parser.ParseFile(token.NewFileSet(), shard, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.GenDecl); ok && decl.Tok == token.VAR {
+				addVars(vars, decl)
+			}
+		}
+	}
+
+	assets := make([]*EmbeddedAsset, 0, len(toc.Elts))
+	for _, elt := range toc.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			return nil, fmt.Errorf("%s: malformed table of contents", file)
+		}
+		name, err := stringLit(kv.Key)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", file, err)
+		}
+		fn := tocFunc(kv.Value)
+		if fn == nil {
+			return nil, fmt.Errorf("%s: malformed table of contents entry %q", file, name)
+		}
+		if _, packed := vars["AssetPack"]; packed {
+			return nil, fmt.Errorf("%s: data of %q is stored in a pack file", file, name)
+		}
+		value, ok := assetData(fn.Name, vars, funcs)
+		if !ok {
+			return nil, fmt.Errorf("%s: no embedded data for %q", file, name)
+		}
+		data, err := dataLit(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s: asset %q: %v", file, name, err)
+		}
+		assets = append(assets, &EmbeddedAsset{
+			Name:       name,
+			Func:       fn.Name,
+			Data:       data,
+			Compressed: compressed,
+			Encrypted:  encrypted,
+		})
+	}
+
+	sort.Sort(byName(assets))
+	return assets, nil
+}
+
+// tocFunc returns the function reading the asset of a table of contents
+// entry: the first element of a {func, func_reader} pair, or the function
+// itself in files generated by earlier versions.
+func tocFunc(entry ast.Expr) *ast.Ident {
+	if lit, ok := entry.(*ast.CompositeLit); ok {
+		if len(lit.Elts) == 0 {
+			return nil
+		}
+		entry = lit.Elts[0]
+	}
+	fn, _ := entry.(*ast.Ident)
+	return fn
+}
+
+// assetData returns the data literal of the asset read by the given
+// function: the value of its _func variable or, in files generated by
+// earlier versions, the literal or variable the function returns,
+// possibly wrapped in a bindata_read call.
+func assetData(fn string, vars map[string]ast.Expr, funcs map[string]*ast.FuncDecl) (ast.Expr, bool) {
+	if v, ok := vars["_"+fn]; ok {
+		return v, true
+	}
+	decl, ok := funcs[fn]
+	if !ok || decl.Body == nil {
+		return nil, false
+	}
+	for _, stmt := range decl.Body.List {
+		ret, ok := stmt.(*ast.ReturnStmt)
+		if !ok || len(ret.Results) == 0 {
+			continue
+		}
+		expr := ret.Results[0]
+		if call, ok := expr.(*ast.CallExpr); ok {
+			if id, ok := call.Fun.(*ast.Ident); ok && id.Name == "bindata_read" && len(call.Args) != 0 {
+				expr = call.Args[0]
+			}
+		}
+		if id, ok := expr.(*ast.Ident); ok {
+			v, ok := vars[id.Name]
+			return v, ok
+		}
+		return expr, true
+	}
+	return nil, false
+}
+
+// isDebugRead reports whether the given bindata_read function is the one
+// of a debug build, which reads the asset from the path it is given.
+func isDebugRead(decl *ast.FuncDecl) bool {
+	if decl == nil || len(decl.Type.Params.List) == 0 {
+		return false
+	}
+	names := decl.Type.Params.List[0].Names
+	return len(names) != 0 && names[0].Name == "path"
+}
+
+// addVars adds the values of the variables declared by decl to vars.
+func addVars(vars map[string]ast.Expr, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		spec := spec.(*ast.ValueSpec)
+		for i, name := range spec.Names {
+			if i < len(spec.Values) {
+				vars[name.Name] = spec.Values[i]
+			}
+		}
+	}
+}
+
+type byName []*EmbeddedAsset
+
+func (p byName) Len() int           { return len(p) }
+func (p byName) Less(i, j int) bool { return p[i].Name < p[j].Name }
+func (p byName) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// stringLit returns the value of a string literal.
+func stringLit(expr ast.Expr) (string, error) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", fmt.Errorf("expected string literal at offset %d", expr.Pos())
+	}
+	return strconv.Unquote(lit.Value)
+}
+
+// dataLit returns the bytes of an embedded data literal, which is either
+// a string literal, a string literal converted to a byte slice or
+// a composite literal of a byte slice.
+func dataLit(expr ast.Expr) ([]byte, error) {
+	switch expr := expr.(type) {
+	case *ast.BasicLit:
+		s, err := stringLit(expr)
+		return []byte(s), err
+	case *ast.CallExpr:
+		if _, ok := expr.Fun.(*ast.ArrayType); ok && len(expr.Args) == 1 {
+			return dataLit(expr.Args[0])
+		}
+	case *ast.CompositeLit:
+		p := make([]byte, 0, len(expr.Elts))
+		for _, elt := range expr.Elts {
+			lit, ok := elt.(*ast.BasicLit)
+			if !ok || lit.Kind != token.INT {
+				return nil, fmt.Errorf("expected byte literal at offset %d", elt.Pos())
+			}
+			b, err := strconv.ParseUint(lit.Value, 0, 8)
+			if err != nil {
+				return nil, err
+			}
+			p = append(p, byte(b))
+		}
+		return p, nil
+	}
+	return nil, fmt.Errorf("unexpected data expression at offset %d", expr.Pos())
+}
@@ -0,0 +1,58 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseFileLegacy(t *testing.T) {
+	names := []string{"in/a/test.asset", "in/b/test.asset", "in/c/test.asset", "in/test.asset"}
+	cases := []struct {
+		file       string
+		compressed bool
+	}{
+		{"compress-memcopy.go", true},
+		{"compress-nomemcopy.go", true},
+		{"nocompress-memcopy.go", false},
+		{"nocompress-nomemcopy.go", false},
+	}
+	for _, cas := range cases {
+		assets, err := ParseFile(filepath.Join("testdata", "out", cas.file))
+		if err != nil {
+			t.Errorf("%s: want err=nil; got %v", cas.file, err)
+			continue
+		}
+		if len(assets) != len(names) {
+			t.Errorf("%s: want len(assets)=%d; got %d", cas.file, len(names), len(assets))
+			continue
+		}
+		for i, asset := range assets {
+			if asset.Name != names[i] {
+				t.Errorf("%s: want name=%s; got %s (i=%d)", cas.file, names[i], asset.Name, i)
+			}
+			if asset.Compressed != cas.compressed {
+				t.Errorf("%s: want compressed=%v; got %v (i=%d)", cas.file, cas.compressed, asset.Compressed, i)
+			}
+			p, err := asset.Content()
+			if err != nil {
+				t.Errorf("%s: want err=nil; got %v (i=%d)", cas.file, err, i)
+				continue
+			}
+			if string(p) != "// sample file\n" {
+				t.Errorf("%s: want content=%q; got %q (i=%d)", cas.file, "// sample file\n", p, i)
+			}
+		}
+	}
+}
+
+func TestParseFileLegacyDebug(t *testing.T) {
+	_, err := ParseFile(filepath.Join("testdata", "out", "debug.go"))
+	if err == nil || !strings.Contains(err.Error(), "debug build") {
+		t.Errorf("want debug build error; got %v", err)
+	}
+}
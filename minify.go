@@ -0,0 +1,404 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// minifiers maps a kind of asset, named after its file extension,
+// to the function minifying it.
+var minifiers = map[string]func([]byte) ([]byte, error){
+	"css":  minifyCSS,
+	"js":   minifyJS,
+	"html": minifyHTML,
+	"json": minifyJSON,
+	"svg":  minifySVG,
+}
+
+// MinifyKinds returns the kinds of assets, which can be minified.
+func MinifyKinds() []string {
+	kinds := make([]string, 0, len(minifiers))
+	for kind := range minifiers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// NewMinifier returns a Transformer minifying assets of the given kind,
+// which is one of the values returned by MinifyKinds. If log is not nil,
+// it is called with the size of each asset before and after minification.
+func NewMinifier(kind string, log func(name string, before, after int)) (Transformer, error) {
+	minify, ok := minifiers[kind]
+	if !ok {
+		return nil, fmt.Errorf("Unknown minifier %q, must be one of: %s", kind, strings.Join(MinifyKinds(), ", "))
+	}
+	return TransformerFunc(func(name string, r io.Reader) (string, io.Reader, error) {
+		p, err := ioutil.ReadAll(r)
+		if err != nil {
+			return "", nil, err
+		}
+		min, err := minify(p)
+		if err != nil {
+			return "", nil, err
+		}
+		if log != nil {
+			log(name, len(p), len(min))
+		}
+		return name, bytes.NewReader(min), nil
+	}), nil
+}
+
+// Minify returns the transformers minifying assets of the given kinds,
+// matched by their file extensions, e.g. "**/*.css" for "css".
+func Minify(kinds []string, log func(name string, before, after int)) ([]TransformConfig, error) {
+	t := make([]TransformConfig, 0, len(kinds))
+	for _, kind := range kinds {
+		m, err := NewMinifier(kind, log)
+		if err != nil {
+			return nil, err
+		}
+		t = append(t, TransformConfig{Pattern: "**/*." + kind, Transformer: m})
+	}
+	return t, nil
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r' || b == '\f'
+}
+
+// skipString copies the string literal starting at p[i] to buf and returns
+// the index right after it. The quote character is p[i].
+func skipString(buf *bytes.Buffer, p []byte, i int) int {
+	j := stringEnd(p, i)
+	buf.Write(p[i:j])
+	return j
+}
+
+// stringEnd returns the index right after the string literal starting
+// at p[i].
+func stringEnd(p []byte, i int) int {
+	q := p[i]
+	j := i + 1
+	for j < len(p) && p[j] != q {
+		if p[j] == '\\' {
+			j++
+		}
+		j++
+	}
+	if j < len(p) {
+		j++
+	}
+	if j > len(p) {
+		j = len(p)
+	}
+	return j
+}
+
+// minifyCSS strips comments and redundant whitespace from a stylesheet.
+// Whitespace is removed only around characters, where it never matters;
+// string literals are kept intact.
+func minifyCSS(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(p))
+	space := false
+	for i := 0; i < len(p); {
+		switch c := p[i]; {
+		case c == '"' || c == '\'':
+			if space && strings.IndexByte("{};,>:", lastByte(&buf)) == -1 {
+				buf.WriteByte(' ')
+			}
+			space = false
+			i = skipString(&buf, p, i)
+		case c == '/' && i+1 < len(p) && p[i+1] == '*':
+			end := bytes.Index(p[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(p)
+			} else {
+				i += end + 4
+			}
+			space = space || buf.Len() != 0
+		case isSpace(c):
+			space = buf.Len() != 0
+			i++
+		default:
+			if strings.IndexByte("{};,>", c) != -1 {
+				space = false
+				if c == '}' {
+					trimTrailing(&buf, ';')
+				}
+			} else if space && !(c == ':' && isDeclaration(p[i:])) {
+				if last := lastByte(&buf); strings.IndexByte("{};,>:", last) == -1 {
+					buf.WriteByte(' ')
+				}
+			}
+			space = false
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// isDeclaration reports whether the colon starting p separates the name
+// and value of a declaration, which is the case unless a block follows,
+// e.g. in the "a :hover {" selector, where the space before it matters.
+func isDeclaration(p []byte) bool {
+	i := bytes.IndexAny(p, "{;}")
+	return i == -1 || p[i] != '{'
+}
+
+func lastByte(buf *bytes.Buffer) byte {
+	if buf.Len() == 0 {
+		return 0
+	}
+	return buf.Bytes()[buf.Len()-1]
+}
+
+func trimTrailing(buf *bytes.Buffer, c byte) {
+	if lastByte(buf) == c {
+		buf.Truncate(buf.Len() - 1)
+	}
+}
+
+// minifyJS strips comments, indentation and blank lines from a script.
+// Line breaks are kept, so automatic semicolon insertion is not affected,
+// and string, template and regular expression literals are kept intact.
+func minifyJS(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(p))
+	space, newline := false, false
+	// regexOK reports whether a slash at this point starts a regular
+	// expression literal rather than a division.
+	regexOK := func() bool {
+		b := bytes.TrimRight(buf.Bytes(), " ")
+		if len(b) == 0 {
+			return true
+		}
+		if c := b[len(b)-1]; strings.IndexByte("(,=:[!&|?{};+-*%<>~^\n", c) != -1 {
+			return true
+		}
+		for _, kw := range []string{"return", "typeof", "case", "do", "else", "in", "of", "void", "yield", "delete", "throw", "new"} {
+			if bytes.HasSuffix(b, []byte(kw)) && (len(b) == len(kw) || !isIdent(b[len(b)-len(kw)-1])) {
+				return true
+			}
+		}
+		return false
+	}
+	// flush writes the pending whitespace before the character c,
+	// unless it is not needed to separate c from the preceding one.
+	flush := func(c byte) {
+		last := lastByte(&buf)
+		if space && !newline && !(isIdent(last) && isIdent(c)) && !ambiguous(last, c) {
+			space = false
+		}
+		if newline {
+			if buf.Len() != 0 {
+				buf.WriteByte('\n')
+			}
+		} else if space && buf.Len() != 0 {
+			buf.WriteByte(' ')
+		}
+		space, newline = false, false
+	}
+	for i := 0; i < len(p); {
+		switch c := p[i]; {
+		case c == '\n':
+			newline = true
+			i++
+		case isSpace(c):
+			space = true
+			i++
+		case c == '/' && i+1 < len(p) && p[i+1] == '/':
+			end := bytes.IndexByte(p[i:], '\n')
+			if end == -1 {
+				end = len(p) - i
+			}
+			i += end
+		case c == '/' && i+1 < len(p) && p[i+1] == '*':
+			end := bytes.Index(p[i+2:], []byte("*/"))
+			if end == -1 {
+				i = len(p)
+			} else {
+				if bytes.IndexByte(p[i:i+end+2], '\n') != -1 {
+					newline = true
+				} else {
+					space = true
+				}
+				i += end + 4
+			}
+		case c == '"' || c == '\'':
+			flush(c)
+			i = skipString(&buf, p, i)
+		case c == '`':
+			flush(c)
+			j := templateEnd(p, i)
+			buf.Write(p[i:j])
+			i = j
+		case c == '/' && regexOK():
+			flush(c)
+			i = skipRegexp(&buf, p, i)
+		default:
+			flush(c)
+			buf.WriteByte(c)
+			i++
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func isIdent(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// ambiguous reports whether removing the space between a and b could
+// change the meaning of a script, e.g. "a + +b", "a - -b" or "1 .toString()",
+// where the dot would become a part of the number.
+func ambiguous(a, b byte) bool {
+	return (a == '+' || a == '-') && (b == '+' || b == '-') ||
+		a == '/' && b == '/' || a == '.' && '0' <= b && b <= '9' ||
+		'0' <= a && a <= '9' && b == '.'
+}
+
+// templateEnd returns the index right after the template literal starting
+// at p[i], skipping the strings, templates and braces nested within its
+// ${...} substitutions.
+func templateEnd(p []byte, i int) int {
+	j := i + 1
+	for j < len(p) && p[j] != '`' {
+		switch {
+		case p[j] == '\\':
+			j += 2
+		case p[j] == '$' && j+1 < len(p) && p[j+1] == '{':
+			j = substitutionEnd(p, j+2)
+		default:
+			j++
+		}
+	}
+	if j < len(p) {
+		j++
+	}
+	if j > len(p) {
+		j = len(p)
+	}
+	return j
+}
+
+// substitutionEnd returns the index right after the closing brace of the
+// template substitution, whose expression starts at p[i].
+func substitutionEnd(p []byte, i int) int {
+	depth := 0
+	for i < len(p) {
+		switch c := p[i]; c {
+		case '"', '\'':
+			i = stringEnd(p, i)
+		case '`':
+			i = templateEnd(p, i)
+		case '{':
+			depth++
+			i++
+		case '}':
+			if depth == 0 {
+				return i + 1
+			}
+			depth--
+			i++
+		default:
+			i++
+		}
+	}
+	return i
+}
+
+// skipRegexp copies the regular expression literal starting at p[i] to buf
+// and returns the index right after it.
+func skipRegexp(buf *bytes.Buffer, p []byte, i int) int {
+	j, class := i+1, false
+	for j < len(p) && p[j] != '\n' {
+		if p[j] == '\\' {
+			j += 2
+			continue
+		}
+		if p[j] == '[' {
+			class = true
+		} else if p[j] == ']' {
+			class = false
+		} else if p[j] == '/' && !class {
+			j++
+			break
+		}
+		j++
+	}
+	for j < len(p) && isIdent(p[j]) {
+		j++
+	}
+	if j > len(p) {
+		j = len(p)
+	}
+	buf.Write(p[i:j])
+	return j
+}
+
+var (
+	regHTMLComment = regexp.MustCompile(`(?s)<!--.*?-->`)
+	regHTMLRaw     = regexp.MustCompile(`(?is)<(pre|textarea|script|style)\b.*?</(pre|textarea|script|style)\s*>`)
+	regSpaces      = regexp.MustCompile(`[ \t\r\n\f]+`)
+	regTagSpaces   = regexp.MustCompile(`>[ \t\r\n\f]+<`)
+)
+
+// minifyHTML strips comments from a document and collapses runs of
+// whitespace into a single space. Conditional comments as well as the
+// content of pre, textarea, script and style elements are kept intact.
+func minifyHTML(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.Grow(len(p))
+	for len(p) != 0 {
+		loc := regHTMLRaw.FindIndex(p)
+		if loc == nil {
+			loc = []int{len(p), len(p)}
+		}
+		text := regHTMLComment.ReplaceAllFunc(p[:loc[0]], func(c []byte) []byte {
+			if bytes.HasPrefix(c, []byte("<!--[if")) {
+				return c
+			}
+			return nil
+		})
+		text = regSpaces.ReplaceAll(text, space)
+		if buf.Len() == 0 {
+			text = bytes.TrimLeft(text, " ")
+		}
+		buf.Write(text)
+		buf.Write(p[loc[0]:loc[1]])
+		p = p[loc[1]:]
+	}
+	return bytes.TrimRight(buf.Bytes(), " "), nil
+}
+
+// minifyJSON removes insignificant whitespace from a JSON document.
+func minifyJSON(p []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, p); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// minifySVG strips comments and whitespace between elements from an SVG
+// image, and collapses whitespace in the remaining text.
+func minifySVG(p []byte) ([]byte, error) {
+	p = regHTMLComment.ReplaceAll(p, nil)
+	p = regTagSpaces.ReplaceAll(p, []byte("><"))
+	p = regSpaces.ReplaceAll(p, space)
+	return bytes.TrimSpace(p), nil
+}
@@ -0,0 +1,59 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import "testing"
+
+func TestMinifyJS(t *testing.T) {
+	cases := []struct {
+		in, out string
+	}{
+		{"var a = 1 ;\n\n  var b = 2;", "var a=1;\nvar b=2;"},
+		{"a + +b; a - -b", "a+ +b;a- -b"},
+		{"x = 1 .toString()", "x=1 .toString()"},
+		{"x = a .b", "x=a.b"},
+		{"x = . 5", "x=. 5"},
+		{"x = a / b / c", "x=a/b/c"},
+		{"x = /a b/g . test(s)", "x=/a b/g.test(s)"},
+		{"s = 'a // b' // comment", "s='a // b'"},
+		{"s = `a  ${ b } c` ;", "s=`a  ${ b } c`;"},
+		{"s = `a ${ `b ${ c } d` } e` ; x = 1", "s=`a ${ `b ${ c } d` } e`;x=1"},
+		{"s = `a ${ { b : '}' }.b } c` ; x = 1", "s=`a ${ { b : '}' }.b } c`;x=1"},
+		{"s = `a \\` ${ \"`\" } b` ; x = 1", "s=`a \\` ${ \"`\" } b`;x=1"},
+	}
+	for i, cas := range cases {
+		out, err := minifyJS([]byte(cas.in))
+		if err != nil {
+			t.Errorf("want err=nil; got %v (i=%d)", err, i)
+			continue
+		}
+		if string(out) != cas.out {
+			t.Errorf("want out=%q; got %q (i=%d)", cas.out, out, i)
+		}
+	}
+}
+
+func TestMinifyCSS(t *testing.T) {
+	cases := []struct {
+		in, out string
+	}{
+		{"a { color: red; }", "a{color:red}"},
+		{"a { color :red }", "a{color:red}"},
+		{"a :hover { color : red ; }", "a :hover{color:red}"},
+		{"a > b , c { margin : 0 auto }", "a>b,c{margin:0 auto}"},
+		{"@media (min-width :1px) { a :first-child { b :c } }", "@media (min-width :1px){a :first-child{b:c}}"},
+		{"/* x */ a { content : \"a ; b\" }", "a{content:\"a ; b\"}"},
+	}
+	for i, cas := range cases {
+		out, err := minifyCSS([]byte(cas.in))
+		if err != nil {
+			t.Errorf("want err=nil; got %v (i=%d)", err, i)
+			continue
+		}
+		if string(out) != cas.out {
+			t.Errorf("want out=%q; got %q (i=%d)", cas.out, out, i)
+		}
+	}
+}
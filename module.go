@@ -0,0 +1,143 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// ErrNoModule is returned by FindModule when no go.mod file was found.
+var ErrNoModule = errors.New("no go.mod file found")
+
+// FindModule looks for the go.mod file in the given directory and its
+// parents. It returns the root directory of the module and its path.
+func FindModule(dir string) (root, path string, err error) {
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+	for {
+		p, err := ioutil.ReadFile(filepath.Join(dir, "go.mod"))
+		if err == nil {
+			m := regModule.FindSubmatch(p)
+			if m == nil {
+				return "", "", fmt.Errorf("%s: missing module directive", filepath.Join(dir, "go.mod"))
+			}
+			return dir, strings.Trim(string(m[1]), `"`+"`"), nil
+		}
+		if !os.IsNotExist(err) {
+			return "", "", err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", "", ErrNoModule
+		}
+		dir = parent
+	}
+}
+
+var regModule = regexp.MustCompile(`(?m)^\s*module\s+(\S+)`)
+
+// GlobModule is the module-aware counterpart of Glob. It looks up the module
+// enclosing the given directory and maps the directories of its data root,
+// e.g. "data", onto the package directories of the module. The longest
+// matching path is treated as a prefix, exactly like Glob does for
+// $GOPATH/data and $GOPATH/src.
+//
+// The package name of each Config is read from the Go files already in
+// the target directory, falling back to the name of the directory.
+//
+// For example, for the following module:
+//
+//	.
+//	├── go.mod
+//	├── data
+//	│   └── web
+//	│       └── static
+//	│           ├── css
+//	│           └── js
+//	└── web
+//	    └── server.go
+//
+// GlobModule(".", "data") will create single Config, where the files would
+// get read recursively from the "data/web" directory and outputted to the
+// "./web/bindata.go" file, in the package declared by "server.go".
+func GlobModule(dir, data string) ([]*Config, error) {
+	root, _, err := FindModule(dir)
+	if err != nil {
+		return nil, err
+	}
+	dataroot := filepath.Join(root, data)
+	switch fi, err := os.Stat(dataroot); {
+	case os.IsNotExist(err):
+		return nil, fmt.Errorf("no data directory %s in module %s found", data, root)
+	case err != nil:
+		return nil, fmt.Errorf("data directory %s of module %s: %v", data, root, err)
+	case !fi.IsDir():
+		return nil, fmt.Errorf("data path %s of module %s is not a directory", data, root)
+	}
+	var dirs []string
+	err = filepath.Walk(dataroot, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !fi.IsDir() {
+			return nil
+		}
+		if path == dataroot {
+			return nil
+		}
+		if name := fi.Name(); name[0] == '.' || name[0] == '_' || name == "testdata" {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(dataroot, path)
+		if err != nil {
+			return err
+		}
+		pkg := filepath.Join(root, rel)
+		if pkg == dataroot || strings.HasPrefix(pkg, dataroot+string(os.PathSeparator)) {
+			return nil
+		}
+		if fi, err := os.Stat(pkg); err == nil && fi.IsDir() {
+			// Keep the longest matching paths only.
+			if n := len(dirs); n != 0 && strings.HasPrefix(rel, dirs[n-1]+string(os.PathSeparator)) {
+				dirs[n-1] = rel
+			} else {
+				dirs = append(dirs, rel)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var cfgs = make([]*Config, 0, len(dirs))
+	for _, dir := range dirs {
+		input := filepath.Join(dataroot, dir)
+		output := filepath.Join(root, dir, "bindata.go")
+		if countdir(input) > 0 {
+			name, err := globPackage(output)
+			if err != nil {
+				return nil, err
+			}
+			cfg := NewConfig()
+			cfg.Package = name
+			cfg.Prefix = input
+			cfg.Output = output
+			cfg.Input = []InputConfig{{Path: input, Recursive: true}}
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	if len(cfgs) == 0 {
+		return nil, fmt.Errorf("no directories of %s matching packages of module %s found", dataroot, root)
+	}
+	return cfgs, nil
+}
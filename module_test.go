@@ -0,0 +1,40 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGlobModuleDataRoot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bindata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/m\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "file"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		data, err string
+	}{
+		{"data", "no data directory data in module "},
+		{"file", "data path file of module "},
+	}
+	for i, cas := range cases {
+		_, err := GlobModule(dir, cas.data)
+		if err == nil || !strings.HasPrefix(err.Error(), cas.err) {
+			t.Errorf("want err=%s...; got %v (i=%d)", cas.err, err, i)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"os"
+)
+
+// tmpSuffix is appended to the names of generated files while they are
+// being written.
+const tmpSuffix = ".tmp"
+
+// outputFiles creates generated files under temporary names next to their
+// destinations and renames them onto those once the generation succeeded,
+// so a failure leaves the files of a previous run intact.
+type outputFiles struct {
+	names []string // Destinations of the temporary files created so far.
+}
+
+// create creates the temporary file for the given destination.
+func (o *outputFiles) create(name string) (*os.File, error) {
+	fd, err := os.Create(name + tmpSuffix)
+	if err != nil {
+		return nil, err
+	}
+
+	o.names = append(o.names, name)
+	return fd, nil
+}
+
+// commit renames the temporary files onto their destinations. The files
+// must be closed.
+func (o *outputFiles) commit() error {
+	for len(o.names) > 0 {
+		err := os.Rename(o.names[0]+tmpSuffix, o.names[0])
+		if err != nil {
+			return err
+		}
+		o.names = o.names[1:]
+	}
+	return nil
+}
+
+// remove removes the temporary files, which were not committed.
+func (o *outputFiles) remove() {
+	for _, name := range o.names {
+		os.Remove(name + tmpSuffix)
+	}
+	o.names = nil
+}
@@ -0,0 +1,82 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+)
+
+// writeOverride writes the lookup of assets in the runtime override
+// directory. This targets release builds.
+func writeOverride(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `var _bindata_override struct {
+	sync.RWMutex
+	dir string
+}
+
+// SetOverrideDir sets the directory, which is looked up for asset files
+// before the embedded data. An asset found there under its name takes
+// precedence over the embedded one. An empty dir, which is the default,
+// falls back to the BINDATA_OVERRIDE environment variable; if that one
+// is empty too, only the embedded assets are used.
+func SetOverrideDir(dir string) {
+	_bindata_override.Lock()
+	_bindata_override.dir = dir
+	_bindata_override.Unlock()
+}
+
+// bindata_override returns the path of the asset file in the override
+// directory, if there is one.
+func bindata_override(name string) (string, bool) {
+	_bindata_override.RLock()
+	dir := _bindata_override.dir
+	_bindata_override.RUnlock()
+
+	if dir == "" {
+		if dir = os.Getenv("BINDATA_OVERRIDE"); dir == "" {
+			return "", false
+		}
+	}
+
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if fi, err := os.Stat(path); err != nil || fi.IsDir() {
+		return "", false
+	}
+	return path, true
+}
+
+// bindata_override_read reads the asset file from the override directory.
+func bindata_override_read(path, name string) ([]byte, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+	return buf, nil
+}
+
+// bindata_override_reader opens the asset file in the override directory.
+func bindata_override_reader(path, name string) (io.ReadCloser, error) {
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+	return fd, nil
+}
+
+`)
+	return err
+}
+
+// writeDebugOverride writes the override API for debug builds, which
+// read the assets from disk anyway.
+func writeDebugOverride(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// SetOverrideDir does nothing in debug builds, which read the assets
+// from their original location on disk.
+func SetOverrideDir(dir string) {}
+
+`)
+	return err
+}
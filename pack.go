@@ -0,0 +1,606 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	packMagic       = "BINDPACK"
+	packTrailerSize = 24
+)
+
+// packWriter writes an asset pack: the data of the assets one after
+// another, followed by the index and the trailer. The index lists the
+// offset, size and name of each asset, prefixed with their lengths, all
+// as uvarints. The trailer holds the size of the whole pack, the offset
+// of the index, both as little-endian uint64, and the magic, so a pack
+// can be found at the end of another file, e.g. an executable.
+type packWriter struct {
+	fd    *os.File
+	w     *bufio.Writer
+	off   int64
+	index bytes.Buffer
+}
+
+func newPackWriter(w io.Writer) *packWriter {
+	return &packWriter{w: bufio.NewWriter(w)}
+}
+
+// createPack creates the pack file with the given name. Without a name
+// the pack is discarded.
+func createPack(name string, out *outputFiles) (*packWriter, error) {
+	if name == "" {
+		return newPackWriter(ioutil.Discard), nil
+	}
+
+	fd, err := out.create(name)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPackWriter(fd)
+	p.fd = fd
+	return p, nil
+}
+
+// add writes the data of the named asset to the pack.
+func (p *packWriter) add(name string, data []byte) error {
+	p.uvarint(uint64(p.off))
+	p.uvarint(uint64(len(data)))
+	p.uvarint(uint64(len(name)))
+	p.index.WriteString(name)
+
+	_, err := p.w.Write(data)
+	p.off += int64(len(data))
+	return err
+}
+
+func (p *packWriter) uvarint(v uint64) {
+	var buf [binary.MaxVarintLen64]byte
+	p.index.Write(buf[:binary.PutUvarint(buf[:], v)])
+}
+
+// close writes the index and the trailer, flushes the pack and closes
+// its file, if it created one.
+func (p *packWriter) close() error {
+	var trailer [packTrailerSize]byte
+	binary.LittleEndian.PutUint64(trailer[0:], uint64(p.off)+uint64(p.index.Len())+packTrailerSize)
+	binary.LittleEndian.PutUint64(trailer[8:], uint64(p.off))
+	copy(trailer[16:], packMagic)
+
+	_, err := p.w.Write(p.index.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = p.w.Write(trailer[:])
+	if err != nil {
+		return err
+	}
+
+	err = p.w.Flush()
+	if p.fd != nil {
+		if e := p.fd.Close(); err == nil {
+			err = e
+		}
+		p.fd = nil
+	}
+	return err
+}
+
+// abort closes the pack file after a failure, if it is still open.
+func (p *packWriter) abort() {
+	if p.fd != nil {
+		p.fd.Close()
+		p.fd = nil
+	}
+}
+
+// packed reports whether the configuration makes a release build, which
+// stores the asset data in a pack rather than in the generated code.
+func (c *Config) packed() bool {
+	return !c.Debug && (c.Pack != "" || c.PackAppended)
+}
+
+// packPath returns the path the generated loader opens the pack at,
+// an empty one for the running executable.
+func (c *Config) packPath() string {
+	if c.PackPath == "" && !c.PackAppended {
+		return c.Pack
+	}
+	return c.PackPath
+}
+
+// packImports returns the packages imported by the pack loader.
+func packImports() []string {
+	return []string{"encoding/binary", "os", "sync"}
+}
+
+// writePackLoader writes the code locating the asset pack at runtime and
+// reading the asset data from it. This targets release builds.
+func writePackLoader(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, `// ErrAssetPack is wrapped by the *AssetError returned by Asset when the
+// asset pack is malformed or does not hold the asset.
+var ErrAssetPack = errors.New("invalid asset pack")
+
+// AssetPack is the path of the file holding the asset pack. A relative
+// path is resolved against the working directory; an empty one refers
+// to the running executable, with the pack appended to it. The
+// BINDATA_PACK environment variable takes precedence, when it is set.
+// The pack is opened on first use, so AssetPack must be set before.
+var AssetPack = %q
+
+var _bindata_pack struct {
+	once  sync.Once
+	err   error
+	file  *os.File
+	size  int64               // Size of the file.
+	base  int64               // Offset of the pack in the file.
+	data  []byte              // Pack mapped into memory, if supported.
+	index map[string][2]int64 // Offset and size of the asset data.
+}
+
+// bindata_pack_open opens the asset pack on first use.
+func bindata_pack_open() error {
+	p := &_bindata_pack
+	p.once.Do(func() {
+		path := AssetPack
+		if env := os.Getenv("BINDATA_PACK"); env != "" {
+			path = env
+		}
+
+		if path == "" {
+			path, p.err = os.Executable()
+			if p.err != nil {
+				return
+			}
+		}
+
+		p.err = bindata_pack_load(path)
+	})
+	return p.err
+}
+
+// bindata_pack_load reads the index of the pack at the end of the given
+// file and maps the file into memory, where the platform supports it.
+func bindata_pack_load(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	err = bindata_pack_index(f)
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	p := &_bindata_pack
+	p.file = f
+	if data, err := bindata_mmap(f, p.size); err == nil && data != nil {
+		p.data = data[p.base:]
+	}
+	return nil
+}
+
+// bindata_pack_index reads the trailer and the index of the pack.
+func bindata_pack_index(f *os.File) error {
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	size := fi.Size()
+	var trailer [%d]byte
+	if size < int64(len(trailer)) {
+		return ErrAssetPack
+	}
+
+	_, err = f.ReadAt(trailer[:], size-int64(len(trailer)))
+	if err != nil {
+		return err
+	}
+
+	if string(trailer[16:]) != %q {
+		return ErrAssetPack
+	}
+
+	n := int64(binary.LittleEndian.Uint64(trailer[0:]))
+	off := int64(binary.LittleEndian.Uint64(trailer[8:]))
+	if n < int64(len(trailer)) || n > size || off < 0 || off > n-int64(len(trailer)) {
+		return ErrAssetPack
+	}
+
+	index := make([]byte, n-int64(len(trailer))-off)
+	_, err = f.ReadAt(index, size-n+off)
+	if err != nil {
+		return err
+	}
+
+	p := &_bindata_pack
+	p.size, p.base = size, size-n
+	p.index = make(map[string][2]int64)
+	for len(index) > 0 {
+		var e [3]uint64
+		for i := range e {
+			v, k := binary.Uvarint(index)
+			if k <= 0 {
+				return ErrAssetPack
+			}
+			e[i], index = v, index[k:]
+		}
+
+		if e[0] > uint64(off) || e[1] > uint64(off)-e[0] || e[2] > uint64(len(index)) {
+			return ErrAssetPack
+		}
+
+		p.index[string(index[:e[2]])] = [2]int64{int64(e[0]), int64(e[1])}
+		index = index[e[2]:]
+	}
+	return nil
+}
+
+// bindata_pack_data returns the data of the named asset. Data mapped
+// into memory is read-only and must not be modified.
+func bindata_pack_data(name string) ([]byte, error) {
+	err := bindata_pack_open()
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	p := &_bindata_pack
+	e, ok := p.index[name]
+	if !ok {
+		return nil, &AssetError{Name: name, Err: ErrAssetPack}
+	}
+
+	if p.data != nil {
+		return p.data[e[0] : e[0]+e[1] : e[0]+e[1]], nil
+	}
+
+	data := make([]byte, e[1])
+	_, err = p.file.ReadAt(data, p.base+e[0])
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+	return data, nil
+}
+
+func bindata_pack_read(name string) ([]byte, error) {
+	data, err := bindata_pack_data(name)
+	if err != nil {
+		return nil, err
+	}
+	return bindata_read(data, name)
+}
+
+func bindata_pack_reader(name string) (io.ReadCloser, error) {
+	data, err := bindata_pack_data(name)
+	if err != nil {
+		return nil, err
+	}
+	return bindata_reader(data, name)
+}
+
+`, c.packPath(), packTrailerSize, packMagic)
+	return err
+}
+
+// writeDebugPack writes the pack API for debug builds, which read the
+// assets from disk rather than from a pack.
+func writeDebugPack(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, `// ErrAssetPack is wrapped by the *AssetError returned by Asset when the
+// asset pack is malformed or does not hold the asset. Debug builds never
+// return it.
+var ErrAssetPack = errors.New("invalid asset pack")
+
+// AssetPack is the path of the file holding the asset pack. Debug builds
+// read the assets from disk and ignore it.
+var AssetPack = %q
+
+`, c.packPath())
+	return err
+}
+
+// writePackFuncs writes the functions which read and stream the asset
+// data stored in the pack.
+func writePackFuncs(w io.Writer, asset *Asset) error {
+	_, err := fmt.Fprintf(w, `func %s() ([]byte, error) {
+	return bindata_pack_read(%q)
+}
+
+func %s_reader() (io.ReadCloser, error) {
+	return bindata_pack_reader(%q)
+}
+
+`, asset.Func, asset.Name, asset.Func, asset.Name)
+	return err
+}
+
+// mmapPlatforms lists the build constraints of the platforms, on which
+// the pack loader maps the pack into memory.
+const mmapPlatforms = "darwin dragonfly freebsd linux netbsd openbsd solaris"
+
+// packLoaderFiles returns the names of the platform specific files of the
+// pack loader next to the given output file, e.g. "bindata_mmap.go" and
+// "bindata_nommap.go" for "bindata.go".
+func packLoaderFiles(output string) []string {
+	base, ext := shardBase(output)
+	return []string{base + "_mmap" + ext, base + "_nommap" + ext}
+}
+
+// writePackPlatform writes the platform specific files of the pack loader:
+// one mapping the pack into memory, the other one making the loader read
+// the assets from the file on demand.
+func writePackPlatform(c *Config, out *outputFiles) error {
+	files := packLoaderFiles(c.Output)
+	nommap := "!" + strings.Replace(mmapPlatforms, " ", ",!", -1)
+
+	err := writePackPlatformFile(out, files[0], c, mmapPlatforms, `import (
+	"os"
+	"syscall"
+)
+
+// bindata_mmap maps the file of the given size into memory read-only.
+func bindata_mmap(f *os.File, size int64) ([]byte, error) {
+	if size == 0 || int64(int(size)) != size {
+		return nil, nil
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+`)
+	if err != nil {
+		return err
+	}
+
+	return writePackPlatformFile(out, files[1], c, nommap, `import (
+	"os"
+)
+
+// bindata_mmap does not map the file on this platform, so the assets
+// are read from it on demand.
+func bindata_mmap(f *os.File, size int64) ([]byte, error) {
+	return nil, nil
+}
+`)
+}
+
+func writePackPlatformFile(out *outputFiles, name string, c *Config, platforms, code string) error {
+	fd, err := out.create(name)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	bfd := bufio.NewWriter(fd)
+	_, err = fmt.Fprintf(bfd, "%s\n\n", generatedLine)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Tags) > 0 {
+		_, err = fmt.Fprintf(bfd, "// +build %s\n", c.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(bfd, "// +build %s\n\npackage %s\n\n%s", platforms, c.Package, code)
+	if err != nil {
+		return err
+	}
+
+	err = bfd.Flush()
+	if err != nil {
+		return err
+	}
+
+	return fd.Close()
+}
+
+// cleanPackFiles removes the platform specific files of the pack loader
+// left over from a previous pack build of the given output file.
+func cleanPackFiles(output string) error {
+	for _, file := range packLoaderFiles(output) {
+		if isGenerated(file) {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writePack adds the asset data to the pack and writes the functions
+// reading it.
+func writePack(w io.Writer, pw *packWriter, asset *Asset, data []byte) error {
+	err := pw.add(asset.Name, data)
+	if err != nil {
+		return err
+	}
+	return writePackFuncs(w, asset)
+}
+
+// AppendPack appends a pack of the assets configured in c to the given
+// file, usually an executable built with a loader generated with the
+// PackAppended option, so the assets can be replaced without recompiling
+// it. A pack appended to the file before is replaced.
+//
+// Only the options affecting the asset data apply: inputs, prefix,
+// ignores, transformers, compression, encryption and size limits. They
+// must match the ones the loader was generated with, so the asset names
+// agree. The assets are streamed into a copy of the file, which replaces
+// it once the new pack is complete, so a failure leaves the file intact.
+func AppendPack(c *Config, file string) (r *Report, err error) {
+	begin := time.Now()
+	r = &Report{Output: file, Pack: file}
+	defer func() {
+		r.Duration = time.Since(begin)
+	}()
+
+	if c.SignKey != nil {
+		err = fmt.Errorf("Signing is not supported with a pack file")
+		return
+	}
+
+	err = c.validateAssets()
+	if err != nil {
+		return
+	}
+
+	toc, err := Assets(c)
+	if err != nil {
+		return
+	}
+
+	if c.ScanSecrets {
+		err = scanSecrets(toc, c.AllowSecrets)
+		if err != nil {
+			return
+		}
+	}
+
+	src, err := os.Open(file)
+	if err != nil {
+		return
+	}
+
+	defer src.Close()
+
+	fi, err := src.Stat()
+	if err != nil {
+		return
+	}
+
+	end, err := packStart(src)
+	if err != nil {
+		return
+	}
+
+	// Write a copy of the file with the new pack next to it, so the file
+	// keeps its old pack until the new one is complete.
+	tmp, err := ioutil.TempFile(filepath.Dir(file), filepath.Base(file)+".*"+tmpSuffix)
+	if err != nil {
+		return
+	}
+
+	defer func() {
+		if err != nil {
+			tmp.Close()
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	err = tmp.Chmod(fi.Mode().Perm())
+	if err != nil {
+		return
+	}
+
+	_, err = io.Copy(tmp, io.NewSectionReader(src, 0, end))
+	if err != nil {
+		return
+	}
+
+	err = writeAppendedPack(tmp, c, toc, r)
+	if err != nil {
+		return
+	}
+
+	err = tmp.Sync()
+	if err != nil {
+		return
+	}
+
+	err = tmp.Close()
+	if err != nil {
+		return
+	}
+
+	src.Close()
+	err = os.Rename(tmp.Name(), file)
+	return
+}
+
+// writeAppendedPack streams the pack of the given assets to w, records
+// them in the report and checks them against the size limits.
+func writeAppendedPack(w io.Writer, c *Config, toc []Asset, r *Report) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pack := newPackWriter(w)
+	names := make(map[string]struct{}, len(toc))
+	for res := range readReleaseAssets(ctx, c, toc) {
+		d := <-res
+		if d.err != nil {
+			return d.err
+		}
+
+		if _, ok := names[d.asset.Name]; ok {
+			return fmt.Errorf("Duplicate asset name %q: %s", d.asset.Name, d.asset.Path)
+		}
+		names[d.asset.Name] = struct{}{}
+
+		begin := time.Now()
+		err := pack.add(d.asset.Name, d.data)
+		if err != nil {
+			return err
+		}
+		d.report.Name, d.report.Func = d.asset.Name, d.asset.Func
+		d.report.Stored = int64(len(d.data))
+		d.report.Duration += time.Since(begin)
+		r.add(d.report)
+	}
+
+	err := checkBudgets(c, r)
+	if err != nil {
+		return err
+	}
+
+	return pack.close()
+}
+
+// packStart returns the offset of the pack appended to the given file,
+// or the size of the file, if it does not end with a pack.
+func packStart(fd *os.File) (int64, error) {
+	fi, err := fd.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	size := fi.Size()
+	if size < packTrailerSize {
+		return size, nil
+	}
+
+	var trailer [packTrailerSize]byte
+	_, err = fd.ReadAt(trailer[:], size-packTrailerSize)
+	if err != nil {
+		return 0, err
+	}
+
+	if string(trailer[16:]) != packMagic {
+		return size, nil
+	}
+
+	n := binary.LittleEndian.Uint64(trailer[0:])
+	if n < packTrailerSize || n > uint64(size) {
+		return 0, fmt.Errorf("%s: malformed pack trailer", fd.Name())
+	}
+
+	return size - int64(n), nil
+}
@@ -0,0 +1,122 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// packageName returns the name of the package, which the Go files in the
+// given directory belong to. The output file and files excluded from the
+// build with an "ignore" constraint are skipped. If the directory holds
+// test files only, the name of the package under test is returned, so
+// the output compiles with both internal and external tests.
+//
+// It returns an empty name if the directory holds no other Go files, and
+// an error if the files declare different packages.
+func packageName(dir, output string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", err
+	}
+
+	sort.Strings(files)
+
+	var srcs, tests []string
+	for _, file := range files {
+		if sameFile(file, output) || shardNumber(output, file) != 0 {
+			continue
+		}
+		if strings.HasSuffix(file, "_test.go") {
+			tests = append(tests, file)
+		} else {
+			srcs = append(srcs, file)
+		}
+	}
+
+	name, err := scanPackage(dir, srcs)
+	if name != "" || err != nil {
+		return name, err
+	}
+
+	return scanPackage(dir, tests)
+}
+
+// scanPackage parses the package clauses of the given files and returns
+// the package name they agree on. The "_test" suffix of external test
+// packages is stripped.
+func scanPackage(dir string, files []string) (string, error) {
+	var name, first string
+	for _, file := range files {
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly|parser.ParseComments)
+		if err != nil {
+			return "", err
+		}
+
+		if isIgnored(f.Comments, f.Package) {
+			continue
+		}
+
+		pkg := strings.TrimSuffix(f.Name.Name, "_test")
+		switch {
+		case name == "":
+			name, first = pkg, file
+		case name != pkg:
+			return "", fmt.Errorf("Conflicting package names in %s: %s (%s) and %s (%s)",
+				dir, name, filepath.Base(first), pkg, filepath.Base(file))
+		}
+	}
+
+	return name, nil
+}
+
+// globPackage returns the package name for an output file found by Glob
+// or GlobModule: the one of the Go files already in its directory, or
+// the name of the directory.
+func globPackage(output string) (string, error) {
+	dir := filepath.Dir(output)
+	name, err := packageName(dir, output)
+	if name != "" || err != nil {
+		return name, err
+	}
+	return safeFunctionName(filepath.Base(dir)), nil
+}
+
+// isIgnored reports whether the build constraints preceding the package
+// clause exclude the file with the "ignore" tag, as generators often do.
+func isIgnored(comments []*ast.CommentGroup, pkg token.Pos) bool {
+	for _, group := range comments {
+		if group.Pos() > pkg {
+			break
+		}
+		for _, c := range group.List {
+			switch strings.TrimSpace(c.Text) {
+			case "//go:build ignore", "// +build ignore":
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameFile reports whether both paths refer to the same file.
+func sameFile(a, b string) bool {
+	fa, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+	fb, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(fa, fb)
+}
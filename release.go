@@ -5,32 +5,170 @@
 package bindata
 
 import (
+	"bytes"
 	"compress/gzip"
+	"context"
+	"crypto/sha256"
 	"fmt"
 	"io"
 	"os"
+	"runtime"
+	"time"
 )
 
-// writeRelease writes the release code file.
-func writeRelease(w io.Writer, c *Config, toc []Asset) error {
+// writeRelease writes the release code file and records the written
+// assets in the given report.
+func writeRelease(ctx context.Context, w io.Writer, c *Config, toc []Asset, r *Report, out *outputFiles) error {
 	err := writeReleaseHeader(w, c)
 	if err != nil {
 		return err
 	}
 
-	for i := range toc {
-		err = writeReleaseAsset(w, c, &toc[i])
+	if c.packed() {
+		err = writePackLoader(w, c)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Cache {
+		err = writeCache(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.Override {
+		err = writeOverride(w)
+		if err != nil {
+			return err
+		}
+	}
+
+	var sums map[string][sha256.Size]byte
+	if c.SignKey != nil {
+		sums = make(map[string][sha256.Size]byte, len(toc))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sw := newShardWriter(w, c, out)
+	defer sw.close()
+
+	write := sw.writeAsset
+	var pack *packWriter
+	if c.packed() {
+		pack, err = createPack(c.Pack, out)
 		if err != nil {
 			return err
 		}
+		defer pack.abort()
+
+		write = func(asset *Asset, data []byte) error {
+			return writePack(w, pack, asset, data)
+		}
+	}
+
+	names := make(map[string]struct{}, len(toc))
+	i := 0
+	for res := range readReleaseAssets(ctx, c, toc) {
+		d := <-res
+		if d.err != nil {
+			return d.err
+		}
+
+		begin := time.Now()
+		toc[i] = d.asset
+		err = write(&toc[i], d.data)
+		if err != nil {
+			return err
+		}
+		d.report.Name, d.report.Func = toc[i].Name, toc[i].Func
+		d.report.Stored = int64(len(d.data))
+		d.report.Duration += time.Since(begin)
+		r.add(d.report)
+
+		if _, ok := names[toc[i].Name]; ok {
+			return fmt.Errorf("Duplicate asset name %q: %s", toc[i].Name, toc[i].Path)
+		}
+		names[toc[i].Name] = struct{}{}
+
+		if sums != nil {
+			sums[toc[i].Name] = sha256.Sum256(d.data)
+		}
+		i++
+	}
+
+	// Reading stopped early, if the context is done.
+	if i < len(toc) {
+		return ctx.Err()
+	}
+
+	err = sw.close()
+	if err != nil {
+		return err
+	}
+	r.Shards = sw.shards
+
+	if pack != nil {
+		err = pack.close()
+		if err != nil {
+			return err
+		}
+		r.Pack = c.Pack
+
+		err = writePackPlatform(c, out)
+		if err != nil {
+			return err
+		}
+	}
+
+	if c.SignKey != nil {
+		return writeSignature(w, c, toc, sums)
 	}
 
 	return nil
 }
 
+// releaseImports returns the packages imported by the release code.
+func releaseImports(c *Config) []string {
+	pkgs := []string{"errors", "fmt", "io", "strings"}
+	if c.NoCompress {
+		pkgs = append(pkgs, "io/ioutil")
+		if !c.NoMemCopy || c.Encrypt {
+			pkgs = append(pkgs, "bytes")
+		}
+	} else {
+		pkgs = append(pkgs, "bytes", "compress/gzip")
+	}
+	if c.Encrypt {
+		pkgs = append(pkgs, "crypto/aes", "crypto/cipher", "crypto/hmac", "crypto/sha256", "sync")
+	} else if c.NoMemCopy {
+		pkgs = append(pkgs, "reflect", "unsafe")
+	}
+	if c.Cache {
+		pkgs = append(pkgs, "container/list", "sync")
+	}
+	if c.Override {
+		pkgs = append(pkgs, "io/ioutil", "os", "path/filepath", "sync")
+	}
+	if c.SignKey != nil {
+		pkgs = append(pkgs, "bytes", "crypto/ed25519", "crypto/sha256")
+	}
+	if c.packed() {
+		pkgs = append(pkgs, packImports()...)
+	}
+	return pkgs
+}
+
 // writeReleaseHeader writes output file headers.
 // This targets release builds.
 func writeReleaseHeader(w io.Writer, c *Config) error {
+	if c.Encrypt {
+		return header_encrypted(w, c)
+	}
+
 	if c.NoCompress {
 		if c.NoMemCopy {
 			return header_uncompressed_nomemcopy(w)
@@ -47,43 +185,143 @@ func writeReleaseHeader(w io.Writer, c *Config) error {
 }
 
 // writeReleaseAsset write a release entry for the given asset.
-// A release entry is a function which embeds and returns
-// the file's byte content.
-func writeReleaseAsset(w io.Writer, c *Config, asset *Asset) error {
-	fd, err := os.Open(asset.Path)
+// A release entry is a variable which embeds the file's byte content
+// and a pair of functions returning and streaming it.
+func writeReleaseAsset(w io.Writer, c *Config, asset *Asset, data []byte) error {
+	var err error
+	switch {
+	case c.Compact:
+		err = data_compact(w, c, asset, data)
+	case c.NoMemCopy:
+		err = data_nomemcopy(w, asset, data)
+	default:
+		err = data_memcopy(w, asset, data)
+	}
+
 	if err != nil {
 		return err
 	}
 
+	return writeReleaseFuncs(w, asset)
+}
+
+// releaseData is an asset read by readReleaseAssets.
+type releaseData struct {
+	asset  Asset       // The asset, renamed if a transformer says so.
+	report AssetReport // Source size, digest and time spent reading.
+	data   []byte      // Data in the form it is embedded in.
+	err    error
+}
+
+// readReleaseAssets reads the given assets with readReleaseAsset, using
+// up to c.Workers goroutines at once. The results are queued in the order
+// of the assets, each delivered through its own channel, so the output
+// stays deterministic no matter which asset is read first. Reading stops
+// early when the context is done.
+func readReleaseAssets(ctx context.Context, c *Config, toc []Asset) <-chan chan *releaseData {
+	workers := c.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(-1)
+	}
+
+	queue := make(chan chan *releaseData, workers-1)
+	go func() {
+		defer close(queue)
+		for i := range toc {
+			res := make(chan *releaseData, 1)
+			select {
+			case queue <- res:
+			case <-ctx.Done():
+				return
+			}
+			go func(asset Asset) {
+				d := &releaseData{asset: asset, report: AssetReport{Path: asset.Path}}
+				if d.err = ctx.Err(); d.err == nil {
+					begin := time.Now()
+					d.data, d.err = readReleaseAsset(c, &d.asset, &d.report)
+					d.report.Duration = time.Since(begin)
+				}
+				res <- d
+			}(toc[i])
+		}
+	}()
+	return queue
+}
+
+// readReleaseAsset reads the content of the given asset in the form
+// it is embedded in: transformed, compressed and encrypted, if configured.
+// The asset is renamed, if a transformer says so.
+func readReleaseAsset(c *Config, asset *Asset, a *AssetReport) ([]byte, error) {
+	fd, err := os.Open(asset.Path)
+	if err != nil {
+		return nil, err
+	}
+
 	defer fd.Close()
 
-	if c.NoCompress {
-		if c.NoMemCopy {
-			return uncompressed_nomemcopy(w, asset, fd)
-		} else {
-			return uncompressed_memcopy(w, asset, fd)
+	src := newDigestReader(fd)
+	r, err := transform(c, asset, src)
+	if err != nil {
+		return nil, err
+	}
+
+	if isTemplate(c, asset) {
+		r, err = checkTemplate(asset.Name, r)
+		if err != nil {
+			return nil, err
 		}
+	}
+
+	var buf bytes.Buffer
+	if c.NoCompress {
+		_, err = io.Copy(&buf, r)
 	} else {
-		if c.NoMemCopy {
-			return compressed_nomemcopy(w, asset, fd)
-		} else {
-			return compressed_memcopy(w, asset, fd)
+		gz := gzip.NewWriter(&buf)
+		_, err = io.Copy(gz, r)
+		if e := gz.Close(); err == nil {
+			err = e
 		}
 	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = src.record(a)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Encrypt {
+		return encrypt(c.Key, asset.Name, buf.Bytes())
+	}
+
+	return buf.Bytes(), nil
 }
 
-func header_compressed_nomemcopy(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `import (
-	"bytes"
-	"compress/gzip"
-	"fmt"
-	"io"
-	"reflect"
-	"strings"
-	"unsafe"
-)
+// writeReleaseFuncs writes the functions which read and stream
+// the embedded asset data.
+func writeReleaseFuncs(w io.Writer, asset *Asset) error {
+	_, err := fmt.Fprintf(w, `func %s() ([]byte, error) {
+	return bindata_read(
+		_%s,
+		%q,
+	)
+}
 
-func bindata_read(data, name string) ([]byte, error) {
+func %s_reader() (io.ReadCloser, error) {
+	return bindata_reader(
+		_%s,
+		%q,
+	)
+}
+
+`, asset.Func, asset.Func, asset.Name, asset.Func, asset.Func, asset.Name)
+	return err
+}
+
+func header_compressed_nomemcopy(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `func bindata_read(data, name string) ([]byte, error) {
 	var empty [0]byte
 	sx := (*reflect.StringHeader)(unsafe.Pointer(&data))
 	b := empty[:]
@@ -94,7 +332,7 @@ func bindata_read(data, name string) ([]byte, error) {
 
 	gz, err := gzip.NewReader(bytes.NewBuffer(b))
 	if err != nil {
-		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+		return nil, &AssetError{Name: name, Err: err}
 	}
 
 	var buf bytes.Buffer
@@ -102,29 +340,31 @@ func bindata_read(data, name string) ([]byte, error) {
 	gz.Close()
 
 	if err != nil {
-		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+		return nil, &AssetError{Name: name, Err: err}
 	}
 
 	return buf.Bytes(), nil
 }
 
+// bindata_reader returns a reader which decompresses the asset data lazily.
+func bindata_reader(data, name string) (io.ReadCloser, error) {
+	gz, err := gzip.NewReader(strings.NewReader(data))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	return gz, nil
+}
+
 `)
 	return err
 }
 
 func header_compressed_memcopy(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `import (
-	"bytes"
-	"compress/gzip"
-	"fmt"
-	"io"
-	"strings"
-)
-
-func bindata_read(data []byte, name string) ([]byte, error) {
+	_, err := fmt.Fprintf(w, `func bindata_read(data []byte, name string) ([]byte, error) {
 	gz, err := gzip.NewReader(bytes.NewBuffer(data))
 	if err != nil {
-		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+		return nil, &AssetError{Name: name, Err: err}
 	}
 
 	var buf bytes.Buffer
@@ -132,25 +372,28 @@ func bindata_read(data []byte, name string) ([]byte, error) {
 	gz.Close()
 
 	if err != nil {
-		return nil, fmt.Errorf("Read %%q: %%v", name, err)
+		return nil, &AssetError{Name: name, Err: err}
 	}
 
 	return buf.Bytes(), nil
 }
 
+// bindata_reader returns a reader which decompresses the asset data lazily.
+func bindata_reader(data []byte, name string) (io.ReadCloser, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, &AssetError{Name: name, Err: err}
+	}
+
+	return gz, nil
+}
+
 `)
 	return err
 }
 
 func header_uncompressed_nomemcopy(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `import (
-	"fmt"
-	"reflect"
-	"strings"
-	"unsafe"
-)
-
-func bindata_read(data, name string) ([]byte, error) {
+	_, err := fmt.Fprintf(w, `func bindata_read(data, name string) ([]byte, error) {
 	var empty [0]byte
 	sx := (*reflect.StringHeader)(unsafe.Pointer(&data))
 	b := empty[:]
@@ -161,110 +404,92 @@ func bindata_read(data, name string) ([]byte, error) {
 	return b, nil
 }
 
+func bindata_reader(data, name string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(strings.NewReader(data)), nil
+}
+
 `)
 	return err
 }
 
 func header_uncompressed_memcopy(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `import (
-	"fmt"
-	"strings"
-)
+	_, err := fmt.Fprintf(w, `func bindata_read(data []byte, name string) ([]byte, error) {
+	buf := make([]byte, len(data))
+	copy(buf, data)
+	return buf, nil
+}
+
+func bindata_reader(data []byte, name string) (io.ReadCloser, error) {
+	return ioutil.NopCloser(bytes.NewReader(data)), nil
+}
+
 `)
 	return err
 }
 
-func compressed_nomemcopy(w io.Writer, asset *Asset, r io.Reader) error {
+func data_nomemcopy(w io.Writer, asset *Asset, data []byte) error {
 	_, err := fmt.Fprintf(w, `var _%s = "`, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	gz := gzip.NewWriter(&StringWriter{Writer: w})
-	_, err = io.Copy(gz, r)
-	gz.Close()
-
+	_, err = (&StringWriter{Writer: w}).Write(data)
 	if err != nil {
 		return err
 	}
 
 	_, err = fmt.Fprintf(w, `"
 
-func %s() ([]byte, error) {
-	return bindata_read(
-		_%s,
-		%q,
-	)
-}
-
-`, asset.Func, asset.Func, asset.Name)
+`)
 	return err
 }
 
-func compressed_memcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `func %s() ([]byte, error) {
-	return bindata_read([]byte{`, asset.Func)
-
-	if err != nil {
-		return nil
+// data_compact writes the data as a raw string literal, if possible, or
+// as an interpreted one escaping non-printable characters only. Without
+// NoMemCopy the literal is converted to a byte slice.
+func data_compact(w io.Writer, c *Config, asset *Asset, data []byte) error {
+	raw := isRawString(data)
+	quote := `"`
+	if raw {
+		quote = "`"
 	}
-
-	gz := gzip.NewWriter(&ByteWriter{Writer: w})
-	_, err = io.Copy(gz, r)
-	gz.Close()
-
-	if err != nil {
-		return err
+	open, close := quote, quote
+	if !c.NoMemCopy {
+		open, close = "[]byte("+quote, quote+")"
 	}
 
-	_, err = fmt.Fprintf(w, `
-	},
-		%q,
-	)
-}
-
-`, asset.Name)
-	return err
-}
-
-func uncompressed_nomemcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `var _%s = "`, asset.Func)
+	_, err := fmt.Fprintf(w, `var _%s = %s`, asset.Func, open)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(&StringWriter{Writer: w}, r)
+	if raw {
+		_, err = w.Write(data)
+	} else {
+		_, err = (&CompactStringWriter{Writer: w}).Write(data)
+	}
 	if err != nil {
 		return err
 	}
 
-	_, err = fmt.Fprintf(w, `"
+	_, err = fmt.Fprintf(w, `%s
 
-func %s() ([]byte, error) {
-	return bindata_read(
-		_%s,
-		%q,
-	)
-}
-
-`, asset.Func, asset.Func, asset.Name)
+`, close)
 	return err
 }
 
-func uncompressed_memcopy(w io.Writer, asset *Asset, r io.Reader) error {
-	_, err := fmt.Fprintf(w, `func %s() ([]byte, error) {
-	return []byte{`, asset.Func)
+func data_memcopy(w io.Writer, asset *Asset, data []byte) error {
+	_, err := fmt.Fprintf(w, `var _%s = []byte{`, asset.Func)
 	if err != nil {
 		return err
 	}
 
-	_, err = io.Copy(&ByteWriter{Writer: w}, r)
+	_, err = (&ByteWriter{Writer: w}).Write(data)
 	if err != nil {
 		return err
 	}
 
 	_, err = fmt.Fprintf(w, `
-	}, nil
 }
 
 `)
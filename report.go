@@ -0,0 +1,86 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"hash"
+	"io"
+	"io/ioutil"
+	"time"
+)
+
+// AssetReport describes a single asset processed by Generate.
+type AssetReport struct {
+	Path     string        `json:"path"`             // Full file path.
+	Name     string        `json:"name"`             // Key used in TOC -- name by which asset is referenced.
+	Func     string        `json:"func"`             // Function name for the procedure returning the asset contents.
+	Size     int64         `json:"size"`             // Size of the source file in bytes.
+	Stored   int64         `json:"stored"`           // Size of the embedded data in bytes; 0 for debug builds.
+	Ratio    float64       `json:"ratio"`            // Stored to source size ratio.
+	Digest   string        `json:"digest,omitempty"` // Hex-encoded SHA-256 digest of the source file; empty for debug builds.
+	Duration time.Duration `json:"duration_ns"`      // Time spent processing the asset.
+}
+
+// Report describes the output file written by Generate. The totals
+// are the sums over all the assets.
+type Report struct {
+	Output   string        `json:"output"`             // Name of the generated file.
+	Shards   []string      `json:"shards,omitempty"`   // Names of the generated shards, if MaxFileSize is set.
+	Pack     string        `json:"pack,omitempty"`     // Name of the pack file written, if Pack is set.
+	Assets   []AssetReport `json:"assets"`             // Processed assets, in the order they were written.
+	Size     int64         `json:"size"`               // Total size of the source files in bytes.
+	Stored   int64         `json:"stored"`             // Total size of the embedded data in bytes.
+	Ratio    float64       `json:"ratio"`              // Total stored to source size ratio.
+	Duration time.Duration `json:"duration_ns"`        // Time spent generating the file.
+	Warnings []string      `json:"warnings,omitempty"` // Exceeded size limits, if BudgetWarnOnly is set.
+}
+
+// add appends the given asset record to the report and updates the totals.
+func (r *Report) add(a AssetReport) {
+	a.Ratio = ratio(a.Stored, a.Size)
+	r.Assets = append(r.Assets, a)
+	r.Size += a.Size
+	r.Stored += a.Stored
+	r.Ratio = ratio(r.Stored, r.Size)
+}
+
+func ratio(stored, size int64) float64 {
+	if size == 0 {
+		return 0
+	}
+	return float64(stored) / float64(size)
+}
+
+// digestReader counts and hashes the bytes read through it.
+type digestReader struct {
+	r io.Reader
+	h hash.Hash
+	n int64
+}
+
+func newDigestReader(r io.Reader) *digestReader {
+	return &digestReader{r: r, h: sha256.New()}
+}
+
+func (d *digestReader) Read(p []byte) (int, error) {
+	n, err := d.r.Read(p)
+	d.h.Write(p[:n])
+	d.n += int64(n)
+	return n, err
+}
+
+// record drains what is left of the underlying reader, in case a
+// transformer did not read it all, and stores its size and digest
+// in the given asset record.
+func (d *digestReader) record(a *AssetReport) error {
+	if _, err := io.Copy(ioutil.Discard, d); err != nil {
+		return err
+	}
+	a.Size = d.n
+	a.Digest = hex.EncodeToString(d.h.Sum(nil))
+	return nil
+}
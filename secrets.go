@@ -0,0 +1,168 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Secret describes a credential found in an asset file.
+type Secret struct {
+	Path string // Full file path.
+	Line int    // Line number of the secret; 0 if it concerns the whole file.
+	Kind string // Kind of the secret, e.g. "AWS access key".
+}
+
+// SecretError is returned by Translate when the ScanSecrets option is set
+// and credentials were found in the assets.
+type SecretError []Secret
+
+func (e SecretError) Error() string {
+	s := make([]string, 0, len(e)+1)
+	s = append(s, fmt.Sprintf("Refusing to embed %d secret(s):", len(e)))
+	for _, secret := range e {
+		if secret.Line > 0 {
+			s = append(s, fmt.Sprintf("\t%s:%d: %s", secret.Path, secret.Line, secret.Kind))
+		} else {
+			s = append(s, fmt.Sprintf("\t%s: %s", secret.Path, secret.Kind))
+		}
+	}
+	return strings.Join(s, "\n")
+}
+
+var secretRules = []struct {
+	kind string
+	re   *regexp.Regexp
+}{
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY( BLOCK)?-----`)},
+	{"AWS access key", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"AWS secret key", regexp.MustCompile(`(?i)aws_?secret_?(access_?)?key\s*["']?\s*[:=]\s*["']?[A-Za-z0-9/+=]{40}`)},
+}
+
+var regToken = regexp.MustCompile(`[A-Za-z0-9+/=_\-]{32,}`)
+
+// regNoToken matches the high-entropy strings of ordinary web assets, which
+// are not credentials: base64 payloads of data URIs and subresource
+// integrity hashes.
+var regNoToken = regexp.MustCompile(`data:[\w.+\-]+/[\w.+\-]+(;[\w.+\-]+=[\w.+\-"]+)*;base64,[A-Za-z0-9+/=]+|\bsha(256|384|512)-[A-Za-z0-9+/]+={0,2}`)
+
+// allowMarker suppresses the secret scan of the line containing it.
+const allowMarker = "bindata:allow"
+
+// sniffSize is the number of bytes at the start of a file, which are
+// looked up for a NUL byte to tell binary files.
+const sniffSize = 8000
+
+// minTokenEntropy is the Shannon entropy in bits per character, above which
+// a token is considered to be a randomly generated credential.
+const minTokenEntropy = 4.5
+
+// scanSecrets looks for credentials in the given assets, skipping the ones
+// matching any of the allow patterns. It returns a SecretError listing
+// every secret found.
+func scanSecrets(toc []Asset, allow []*regexp.Regexp) error {
+	var secrets SecretError
+LOOP:
+	for i := range toc {
+		for _, re := range allow {
+			if re.MatchString(toc[i].Path) || re.MatchString(toc[i].Name) {
+				continue LOOP
+			}
+		}
+		found, err := scanFile(toc[i].Path)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, found...)
+	}
+	if len(secrets) != 0 {
+		return secrets
+	}
+	return nil
+}
+
+// scanFile looks for credentials in a single file, line by line. Lines
+// containing the allow marker are skipped. Binary files are only checked
+// by name.
+func scanFile(path string) ([]Secret, error) {
+	var secrets []Secret
+	if base := filepath.Base(path); base == ".env" || strings.HasPrefix(base, ".env.") {
+		secrets = append(secrets, Secret{Path: path, Kind: "environment file"})
+	}
+
+	fd, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer fd.Close()
+
+	r := bufio.NewReaderSize(fd, sniffSize)
+	head, err := r.Peek(sniffSize)
+	if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+		return nil, err
+	}
+
+	if bytes.IndexByte(head, 0) != -1 {
+		return secrets, nil
+	}
+
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if len(line) != 0 && !bytes.Contains(line, []byte(allowMarker)) {
+			secrets = append(secrets, scanLine(path, n, line)...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return secrets, nil
+}
+
+// scanLine looks for credentials in the n-th line of a file.
+func scanLine(path string, n int, line []byte) []Secret {
+	var secrets []Secret
+	for _, rule := range secretRules {
+		if rule.re.Match(line) {
+			secrets = append(secrets, Secret{Path: path, Line: n, Kind: rule.kind})
+		}
+	}
+	line = regNoToken.ReplaceAll(line, nil)
+	for _, token := range regToken.FindAll(line, -1) {
+		if entropy(token) > minTokenEntropy {
+			secrets = append(secrets, Secret{Path: path, Line: n, Kind: "high-entropy token"})
+			break
+		}
+	}
+	return secrets
+}
+
+// entropy returns the Shannon entropy of p in bits per byte.
+func entropy(p []byte) float64 {
+	var freq [256]int
+	for _, b := range p {
+		freq[b]++
+	}
+	var e float64
+	for _, n := range freq {
+		if n != 0 {
+			f := float64(n) / float64(len(p))
+			e -= f * math.Log2(f)
+		}
+	}
+	return e
+}
@@ -0,0 +1,197 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// shardWriter writes the release entries of the assets to the output file
+// until the size of its entries reaches c.MaxFileSize, and spills the
+// following ones into shards, each of them filled up to the same size.
+type shardWriter struct {
+	c      *Config
+	out    *outputFiles  // Files the shards are created with.
+	w      io.Writer     // Output the entries are currently written to.
+	size   int64         // Size of the entries written to w.
+	n      int           // Number of the current shard; 0 for the output file.
+	fd     *os.File      // Current shard file.
+	bfd    *bufio.Writer // Buffered writer of the current shard file.
+	shards []string      // Names of the shards written so far.
+	buf    bytes.Buffer
+}
+
+func newShardWriter(w io.Writer, c *Config, out *outputFiles) *shardWriter {
+	return &shardWriter{c: c, out: out, w: w}
+}
+
+// writeAsset writes the release entry of the given asset.
+func (sw *shardWriter) writeAsset(asset *Asset, data []byte) error {
+	if sw.c.MaxFileSize <= 0 {
+		return writeReleaseAsset(sw.w, sw.c, asset, data)
+	}
+
+	sw.buf.Reset()
+	err := writeReleaseAsset(&sw.buf, sw.c, asset, data)
+	if err != nil {
+		return err
+	}
+
+	if sw.size != 0 && sw.size+int64(sw.buf.Len()) > sw.c.MaxFileSize {
+		err = sw.next()
+		if err != nil {
+			return err
+		}
+	}
+
+	sw.size += int64(sw.buf.Len())
+	_, err = sw.w.Write(sw.buf.Bytes())
+	return err
+}
+
+// next closes the current shard, if any, and starts the next one.
+func (sw *shardWriter) next() error {
+	err := sw.close()
+	if err != nil {
+		return err
+	}
+
+	sw.n++
+	name := shardName(sw.c.Output, sw.n)
+	sw.fd, err = sw.out.create(name)
+	if err != nil {
+		return err
+	}
+
+	sw.shards = append(sw.shards, name)
+	sw.bfd = bufio.NewWriter(sw.fd)
+	sw.w, sw.size = sw.bfd, 0
+	return writeShardHeader(sw.bfd, sw.c)
+}
+
+// close flushes and closes the current shard, if any.
+func (sw *shardWriter) close() error {
+	if sw.fd == nil {
+		return nil
+	}
+
+	err := sw.bfd.Flush()
+	if e := sw.fd.Close(); err == nil {
+		err = e
+	}
+
+	sw.fd, sw.bfd = nil, nil
+	return err
+}
+
+// writeShardHeader writes the header, package clause and imports of
+// a shard file.
+func writeShardHeader(w io.Writer, c *Config) error {
+	_, err := fmt.Fprintf(w, "%s\n\n", generatedLine)
+	if err != nil {
+		return err
+	}
+
+	if len(c.Tags) > 0 {
+		_, err = fmt.Fprintf(w, "// +build %s\n\n", c.Tags)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, "package %s\n\nimport (\n\t\"io\"\n)\n\n", c.Package)
+	return err
+}
+
+// shardName returns the name of the n-th shard of the given output file,
+// e.g. "bindata_001.go" for "bindata.go". Shards of a test file are test
+// files as well, e.g. "bindata_001_test.go".
+func shardName(output string, n int) string {
+	base, ext := shardBase(output)
+	return fmt.Sprintf("%s_%03d%s", base, n, ext)
+}
+
+func shardBase(output string) (base, ext string) {
+	base, ext = strings.TrimSuffix(output, ".go"), ".go"
+	if strings.HasSuffix(base, "_test") {
+		base, ext = strings.TrimSuffix(base, "_test"), "_test.go"
+	}
+	return base, ext
+}
+
+// shardNumber returns the number of the shard of the given output file,
+// which the file is, or 0 if it is not one.
+func shardNumber(output, file string) int {
+	base, ext := shardBase(output)
+	if filepath.Dir(file) != filepath.Dir(base) {
+		return 0
+	}
+	file, base = filepath.Base(file), filepath.Base(base)
+	if !strings.HasPrefix(file, base+"_") || !strings.HasSuffix(file, ext) {
+		return 0
+	}
+	num := file[len(base)+1 : len(file)-len(ext)]
+	if len(num) < 3 {
+		return 0
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return 0
+	}
+	return n
+}
+
+// shardFiles returns the names of the existing shards of the given output
+// file, which were generated by bindata.
+func shardFiles(output string) ([]string, error) {
+	base, ext := shardBase(output)
+	files, err := filepath.Glob(base + "_*" + ext)
+	if err != nil {
+		return nil, err
+	}
+	shards := files[:0]
+	for _, file := range files {
+		if shardNumber(output, file) != 0 && isGenerated(file) {
+			shards = append(shards, file)
+		}
+	}
+	return shards, nil
+}
+
+// cleanShards removes the shards of the given output file left over from
+// previous runs, i.e. the ones numbered above the given number of shards.
+func cleanShards(output string, keep int) error {
+	shards, err := shardFiles(output)
+	if err != nil {
+		return err
+	}
+	for _, file := range shards {
+		if shardNumber(output, file) > keep {
+			if err := os.Remove(file); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// isGenerated reports whether the file starts with the comment marking
+// it as generated by bindata.
+func isGenerated(file string) bool {
+	fd, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer fd.Close()
+	line, _ := bufio.NewReader(fd).ReadString('\n')
+	return strings.TrimRight(line, "\r\n") == generatedLine
+}
@@ -0,0 +1,135 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// signedAssets returns the names of the given assets in the order
+// their digests are signed in.
+func signedAssets(toc []Asset) []string {
+	names := make([]string, 0, len(toc))
+	for i := range toc {
+		names = append(names, toc[i].Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// signatureMessage builds the signed message out of the asset digests.
+// It has the format of the sha256sum tool output, e.g.:
+//
+//	0b8e6fba2c8b5e63...  pub/style/foo.css
+//
+// The generated VerifyAssets function must build the very same message.
+func signatureMessage(names []string, sums map[string][sha256.Size]byte) []byte {
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%x  %s\n", sums[name], name)
+	}
+	return buf.Bytes()
+}
+
+// writeSignature signs the digests of the embedded asset data and writes
+// the VerifyAssets function checking them. This targets release builds.
+func writeSignature(w io.Writer, c *Config, toc []Asset, sums map[string][sha256.Size]byte) error {
+	names := signedAssets(toc)
+	sig := ed25519.Sign(c.SignKey, signatureMessage(names, sums))
+
+	funcs := make(map[string]string, len(toc))
+	for i := range toc {
+		funcs[toc[i].Name] = toc[i].Func
+	}
+
+	typ := "[]byte"
+	if c.NoMemCopy {
+		typ = "string"
+	}
+
+	_, err := fmt.Fprintf(w, `// ErrAssetSignature is returned by VerifyAssets when the embedded data
+// does not match the signature created at generation time.
+var ErrAssetSignature = errors.New("asset signature verification failed")
+
+// _bindata_signature is the signature of the embedded asset digests.
+var _bindata_signature = []byte{`)
+	if err != nil {
+		return err
+	}
+
+	_, err = (&ByteWriter{Writer: w}).Write(sig)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, `
+}
+
+// _bindata_signed lists the embedded data of each asset in the order,
+// in which their digests are signed.
+var _bindata_signed = []struct {
+	name string
+	data %s
+}{
+`, typ)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		_, err = fmt.Fprintf(w, "\t{%q, _%s},\n", name, funcs[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, `}
+
+// VerifyAssets recomputes the digests of the embedded assets and checks
+// them against the signature created at generation time, using the given
+// public key. It returns ErrAssetSignature if any asset was tampered with,
+// added or removed. Files in an override directory are not verified.
+func VerifyAssets(pub ed25519.PublicKey) error {
+	if len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("VerifyAssets: invalid public key size %%d", len(pub))
+	}
+
+	var msg bytes.Buffer
+	for _, a := range _bindata_signed {
+		fmt.Fprintf(&msg, "%%x  %%s\n", sha256.Sum256([]byte(a.data)), a.name)
+	}
+
+	if !ed25519.Verify(pub, msg.Bytes(), _bindata_signature) {
+		return ErrAssetSignature
+	}
+
+	return nil
+}
+
+`)
+	return err
+}
+
+// writeDebugVerify writes the VerifyAssets function for debug builds,
+// which have no embedded data to verify.
+func writeDebugVerify(w io.Writer) error {
+	_, err := fmt.Fprintf(w, `// ErrAssetSignature is returned by VerifyAssets when the embedded data
+// does not match the signature created at generation time.
+var ErrAssetSignature = errors.New("asset signature verification failed")
+
+// VerifyAssets does nothing in debug builds, which read the assets
+// from disk. It always returns nil.
+func VerifyAssets(pub ed25519.PublicKey) error {
+	return nil
+}
+
+`)
+	return err
+}
@@ -5,32 +5,87 @@
 package bindata
 
 import (
+	"bytes"
 	"io"
+	"unicode/utf8"
 )
 
 const lowerHex = "0123456789abcdef"
 
+// StringWriter writes the bytes as "\xNN" escapes of a string literal.
 type StringWriter struct {
 	io.Writer
-	c int
+	c   int
+	buf []byte
 }
 
+// Write encodes p in chunks and writes them to the underlying writer.
+// It returns the number of bytes of p, whose encoding was written,
+// and the first error of the underlying writer.
 func (w *StringWriter) Write(p []byte) (n int, err error) {
-	if len(p) == 0 {
-		return
+	for len(p) != 0 {
+		chunk := p[:min(len(p), chunkSize)]
+		buf := w.buf[:0]
+		for _, b := range chunk {
+			buf = append(buf, '\\', 'x', lowerHex[b>>4], lowerHex[b&0xf])
+		}
+		w.buf = buf
+		w.c += len(chunk)
+
+		if _, err = w.Writer.Write(buf); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
 	}
+	return n, nil
+}
 
-	buf := []byte(`\x00`)
-	var b byte
+// CompactStringWriter writes the bytes as the content of an interpreted
+// string literal, keeping printable ASCII characters as they are and
+// escaping only the remaining ones.
+type CompactStringWriter struct {
+	io.Writer
+	buf []byte
+}
 
-	for n, b = range p {
-		buf[2] = lowerHex[b/16]
-		buf[3] = lowerHex[b%16]
-		w.Writer.Write(buf)
-		w.c++
-	}
+// Write encodes p in chunks and writes them to the underlying writer.
+// It returns the number of bytes of p, whose encoding was written,
+// and the first error of the underlying writer.
+func (w *CompactStringWriter) Write(p []byte) (n int, err error) {
+	for len(p) != 0 {
+		chunk := p[:min(len(p), chunkSize)]
+		buf := w.buf[:0]
+		for _, b := range chunk {
+			switch {
+			case b == '"' || b == '\\':
+				buf = append(buf, '\\', b)
+			case b == '\n':
+				buf = append(buf, '\\', 'n')
+			case b == '\t':
+				buf = append(buf, '\\', 't')
+			case b == '\r':
+				buf = append(buf, '\\', 'r')
+			case b >= ' ' && b < 0x7f:
+				buf = append(buf, b)
+			default:
+				buf = append(buf, '\\', 'x', lowerHex[b>>4], lowerHex[b&0xf])
+			}
+		}
+		w.buf = buf
 
-	n++
+		if _, err = w.Writer.Write(buf); err != nil {
+			return n, err
+		}
+		n += len(chunk)
+		p = p[len(chunk):]
+	}
+	return n, nil
+}
 
-	return
+// isRawString reports whether p can be written verbatim as a raw string
+// literal: it must be valid UTF-8 without backquotes, carriage returns,
+// which raw strings drop, and characters Go source must not contain.
+func isRawString(p []byte) bool {
+	return utf8.Valid(p) && bytes.IndexAny(p, "`\r\x00\ufeff") == -1
 }
@@ -0,0 +1,117 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"text/template/parse"
+)
+
+// templatePackage returns the import path of the template package used
+// by the generated Templates function.
+func templatePackage(c *Config) string {
+	if c.TextTemplates {
+		return "text/template"
+	}
+	return "html/template"
+}
+
+// isTemplate reports whether the asset is parsed by the generated
+// Templates function.
+func isTemplate(c *Config, asset *Asset) bool {
+	return c.Templates != "" && matchPattern(c.Templates, asset.Name)
+}
+
+// checkTemplate parses the template read from r and returns an error
+// describing its syntax errors, if any. Template functions are not checked,
+// since they are registered at runtime. It returns a reader of the very
+// same content.
+func checkTemplate(name string, r io.Reader) (io.Reader, error) {
+	p, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	t := parse.New(name)
+	t.Mode = parse.SkipFuncCheck
+	if _, err = t.Parse(string(p), "", "", make(map[string]*parse.Tree)); err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(p), nil
+}
+
+// checkTemplateFile parses the template file of the given asset.
+func checkTemplateFile(asset *Asset) error {
+	fd, err := os.Open(asset.Path)
+	if err != nil {
+		return err
+	}
+
+	defer fd.Close()
+
+	_, err = checkTemplate(asset.Name, fd)
+	return err
+}
+
+// writeTemplates writes the Templates function, which parses the template
+// assets into a single template set.
+func writeTemplates(w io.Writer, c *Config, toc []Asset) error {
+	var names []string
+	for i := range toc {
+		if isTemplate(c, &toc[i]) {
+			names = append(names, toc[i].Name)
+		}
+	}
+	sort.Strings(names)
+
+	_, err := fmt.Fprintf(w, `var _bindata_funcs template.FuncMap
+
+// SetTemplateFuncs registers the functions available to the templates
+// parsed by Templates. It must be called before Templates.
+func SetTemplateFuncs(funcs template.FuncMap) {
+	_bindata_funcs = funcs
+}
+
+// Templates parses all the template assets matching the %q pattern
+// into a single template set. Each template is named by its asset name.
+func Templates() (*template.Template, error) {
+	t := template.New("").Funcs(_bindata_funcs)
+	for _, name := range _bindata_templates {
+		data, err := Asset(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err = t.New(name).Parse(string(data)); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// _bindata_templates lists the names of the template assets.
+var _bindata_templates = []string{
+`, c.Templates)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		_, err = fmt.Fprintf(w, "\t%q,\n", name)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = fmt.Fprintf(w, `}
+
+`)
+	return err
+}
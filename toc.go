@@ -10,8 +10,8 @@ import (
 )
 
 // writeTOC writes the table of contents file.
-func writeTOC(w io.Writer, toc []Asset) error {
-	err := writeTOCHeader(w)
+func writeTOC(w io.Writer, c *Config, toc []Asset) error {
+	err := writeTOCHeader(w, c)
 	if err != nil {
 		return err
 	}
@@ -27,16 +27,75 @@ func writeTOC(w io.Writer, toc []Asset) error {
 }
 
 // writeTOCHeader writes the table of contents file header.
-func writeTOCHeader(w io.Writer) error {
-	_, err := fmt.Fprintf(w, `// Asset loads and returns the asset for the given name.
-// It returns an error if the asset could not be found or
+func writeTOCHeader(w io.Writer, c *Config) error {
+	read, override, overrideReader := "a.read()", "", ""
+	if !c.Debug {
+		if c.Cache {
+			read = "bindata_cached(cannonicalName, a.read)"
+		}
+		if c.Override {
+			override = `if path, ok := bindata_override(cannonicalName); ok {
+			return bindata_override_read(path, cannonicalName)
+		}
+		`
+			overrideReader = `if path, ok := bindata_override(cannonicalName); ok {
+			return bindata_override_reader(path, cannonicalName)
+		}
+		`
+		}
+	}
+
+	_, err := fmt.Fprintf(w, `// ErrAssetNotFound is the error wrapped by an *AssetError when no asset
+// matches the requested name.
+var ErrAssetNotFound = errors.New("asset not found")
+
+// AssetError records a failure to load or open the named asset.
+type AssetError struct {
+	Name string // Name of the asset.
+	Err  error  // Underlying error, e.g. ErrAssetNotFound.
+}
+
+func (e *AssetError) Error() string {
+	return fmt.Sprintf("Asset %%s: %%v", e.Name, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *AssetError) Unwrap() error {
+	return e.Err
+}
+
+// Asset loads and returns the asset for the given name.
+// It returns an *AssetError if the asset could not be found or
 // could not be loaded.
 func Asset(name string) ([]byte, error) {
 	cannonicalName := strings.Replace(name, "\\", "/", -1)
-	if f, ok := _bindata[cannonicalName]; ok {
-		return f()
+	if a, ok := _bindata[cannonicalName]; ok {
+		%sreturn %s
+	}
+	return nil, &AssetError{Name: name, Err: ErrAssetNotFound}
+}
+
+// MustAsset is like Asset, but panics if the asset could not be found or
+// could not be loaded. It simplifies safe initialization of global variables.
+func MustAsset(name string) []byte {
+	data, err := Asset(name)
+	if err != nil {
+		panic(fmt.Sprintf("bindata: MustAsset: %%v", err))
+	}
+	return data
+}
+
+// AssetReader returns a reader streaming the contents of the asset for
+// the given name. Unlike Asset, it does not load the whole asset into
+// memory. It is the caller's responsibility to close the reader.
+// It returns an *AssetError if the asset could not be found or
+// could not be opened.
+func AssetReader(name string) (io.ReadCloser, error) {
+	cannonicalName := strings.Replace(name, "\\", "/", -1)
+	if a, ok := _bindata[cannonicalName]; ok {
+		%sreturn a.reader()
 	}
-	return nil, fmt.Errorf("Asset %%s not found", name)
+	return nil, &AssetError{Name: name, Err: ErrAssetNotFound}
 }
 
 // AssetNames returns the names of the assets.
@@ -48,15 +107,21 @@ func AssetNames() []string {
 	return names
 }
 
+// bindata_asset holds the functions reading and streaming a single asset.
+type bindata_asset struct {
+	read   func() ([]byte, error)
+	reader func() (io.ReadCloser, error)
+}
+
 // _bindata is a table, holding each asset generator, mapped to its name.
-var _bindata = map[string]func() ([]byte, error){
-`)
+var _bindata = map[string]bindata_asset{
+`, override, read, overrideReader)
 	return err
 }
 
 // writeTOCAsset write a TOC entry for the given asset.
 func writeTOCAsset(w io.Writer, asset *Asset) error {
-	_, err := fmt.Fprintf(w, "\t%q: %s,\n", asset.Name, asset.Func)
+	_, err := fmt.Fprintf(w, "\t%q: {%s, %s_reader},\n", asset.Name, asset.Func, asset.Func)
 	return err
 }
 
@@ -0,0 +1,94 @@
+// This work is subject to the CC0 1.0 Universal (CC0 1.0) Public Domain Dedication
+// license. Its contents can be found at:
+// http://creativecommons.org/publicdomain/zero/1.0/
+
+package bindata
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Transformer preprocesses an asset between reading it from disk and
+// compressing it, e.g. minifies it, precompiles a template or converts
+// its format.
+type Transformer interface {
+	// Transform takes the name and the content of an asset and returns
+	// the name and the content to embed instead. The returned name may
+	// differ from the given one, which renames the asset.
+	Transform(name string, r io.Reader) (string, io.Reader, error)
+}
+
+// TransformerFunc is an adapter allowing the use of an ordinary function
+// as a Transformer.
+type TransformerFunc func(name string, r io.Reader) (string, io.Reader, error)
+
+// Transform calls f(name, r).
+func (f TransformerFunc) Transform(name string, r io.Reader) (string, io.Reader, error) {
+	return f(name, r)
+}
+
+// TransformConfig registers a Transformer for the assets matching a pattern.
+type TransformConfig struct {
+	// Pattern is a glob pattern matched against the asset name, as
+	// accepted by path.Match. Additionally a "**" path element matches
+	// any number of directories, e.g. "templates/**/*.tmpl".
+	Pattern string
+
+	// Transformer is applied to each matching asset.
+	Transformer Transformer
+}
+
+// transform applies the transformers configured for the given asset in
+// order. Each transformer is matched against the asset name as returned
+// by the previous one. The asset is renamed, if needed.
+func transform(c *Config, asset *Asset, r io.Reader) (io.Reader, error) {
+	name := asset.Name
+	for _, t := range c.Transformers {
+		if !matchPattern(t.Pattern, name) {
+			continue
+		}
+		var err error
+		if name, r, err = t.Transformer.Transform(name, r); err != nil {
+			return nil, fmt.Errorf("Transform %s: %v", asset.Name, err)
+		}
+		if name == "" {
+			return nil, fmt.Errorf("Transform %s: empty asset name", asset.Name)
+		}
+	}
+	if name != asset.Name {
+		asset.Name = name
+		asset.Func = safeFunctionName(name)
+	}
+	return r, nil
+}
+
+// matchPattern reports whether the slash-separated name matches the pattern.
+// In addition to the path.Match syntax, a "**" element of the pattern
+// matches zero or more elements of the name.
+func matchPattern(pattern, name string) bool {
+	return matchElems(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchElems(pattern, name []string) bool {
+	for len(pattern) != 0 {
+		if pattern[0] == "**" {
+			for i := len(name); i >= 0; i-- {
+				if matchElems(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); !ok || err != nil {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}